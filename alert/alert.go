@@ -0,0 +1,175 @@
+// Package alert evaluates rules over the stats of Nitrado game servers, such
+// as a memory usage threshold or a sudden drop of the player count, and
+// sends an alert through a Notifier when a rule starts or stops firing.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// defaultInterval is used when Engine.Interval is not set.
+const defaultInterval = time.Minute
+
+// Alert is sent when a rule starts firing, and again with Resolved set when
+// it stops.
+type Alert struct {
+	Rule      string    `json:"rule"`
+	ServiceID int       `json:"service_id"`
+	Resolved  bool      `json:"resolved"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
+
+// String returns a one line summary of the alert.
+func (a Alert) String() string {
+	if a.Resolved {
+		return fmt.Sprintf("[resolved] %s on service %d", a.Rule, a.ServiceID)
+	}
+	return fmt.Sprintf("[firing] %s on service %d: %s", a.Rule, a.ServiceID, a.Message)
+}
+
+// Notifier sends alerts. Use notify.Alerts to send alerts to Discord or a
+// webhook.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(ctx context.Context, a Alert) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, a Alert) error {
+	return f(ctx, a)
+}
+
+// namedRule is a rule added to an Engine.
+type namedRule struct {
+	name string
+	rule Rule
+}
+
+// Engine periodically evaluates its rules over the stats of a game server.
+type Engine struct {
+	client    *nitrado.Client
+	serviceID int
+	notifier  Notifier
+	rules     []namedRule
+
+	Interval time.Duration // How often to check the rules, defaults to 1 minute.
+
+	// OnError is called when a check fails. Alerts which could not be sent
+	// are sent again on the next check.
+	OnError func(error)
+
+	checking sync.Mutex // Serializes Check.
+	mu       sync.Mutex
+	firing   map[string]bool
+}
+
+// NewEngine returns an Engine for the game server of the given service,
+// which sends alerts to n.
+func NewEngine(c *nitrado.Client, serviceID int, n Notifier) *Engine {
+	return &Engine{client: c, serviceID: serviceID, notifier: n, firing: make(map[string]bool)}
+}
+
+// Add adds a rule. The name identifies the rule in its alerts.
+func (e *Engine) Add(name string, r Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = append(e.rules, namedRule{name: name, rule: r})
+}
+
+// Run checks the rules immediately and then every Interval until ctx is
+// cancelled.
+func (e *Engine) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if e.OnError != nil {
+				e.OnError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the game server and its stats once, evaluates every rule,
+// and notifies the rules which started or stopped firing since the previous
+// check. It returns the alerts which were sent. When the game server or its
+// stats cannot be fetched, or the API returns an error, the error is
+// returned and no rule changes state.
+func (e *Engine) Check(ctx context.Context) ([]Alert, error) {
+	e.checking.Lock()
+	defer e.checking.Unlock()
+
+	gs, _, err := e.client.GameServers.Get(e.serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting game server: %w", err)
+	}
+	stats, _, err := e.client.GameServerStats.Get(e.serviceID, nitrado.GSStatsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting stats: %w", err)
+	}
+
+	var pending []Alert
+	e.mu.Lock()
+	for _, r := range e.rules {
+		firing, msg := r.rule.Evaluate(gs, stats)
+		if firing != e.firing[r.name] {
+			pending = append(pending, Alert{Rule: r.name, ServiceID: e.serviceID, Resolved: !firing, Message: msg, Time: time.Now()})
+		}
+	}
+	e.mu.Unlock()
+
+	// Alerts are sent without holding the lock, so that Firing does not
+	// wait for the notifier.
+	var sent []Alert
+	var first error
+	for _, a := range pending {
+		if err := e.notifier.Notify(ctx, a); err != nil {
+			if first == nil {
+				first = fmt.Errorf("notifying %q: %w", a.Rule, err)
+			}
+			continue
+		}
+		e.mu.Lock()
+		e.firing[a.Rule] = !a.Resolved
+		e.mu.Unlock()
+		sent = append(sent, a)
+	}
+
+	return sent, first
+}
+
+// Firing returns the names of the rules which are firing.
+func (e *Engine) Firing() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var names []string
+	for _, r := range e.rules {
+		if e.firing[r.name] {
+			names = append(names, r.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,134 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/danstis/go-nitrado/nitradotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// players sets the current player count stats of the game server from a
+// list of [value, unix timestamp] pairs.
+func players(t *testing.T, server *nitradotest.Server, points string) {
+	var stats nitrado.GSStats
+	require.Nil(t, json.Unmarshal([]byte(points), &stats.CurrentPlayers))
+	server.SetStats(7654321, stats)
+}
+
+// TestEngine_Check tests the Engine Check() method.
+func TestEngine_Check(t *testing.T) {
+	server := nitradotest.NewServer()
+	defer server.Close()
+	server.Now = func() time.Time { return time.Unix(1622360200, 0) }
+	server.AddGameServer(nitrado.Service{ID: 7654321}, nitrado.GameServer{Slots: 10})
+	client := server.Client()
+	var notified []Alert
+	fail := false
+	engine := NewEngine(client, 7654321, NotifierFunc(func(ctx context.Context, a Alert) error {
+		if fail {
+			return errors.New("discord is down")
+		}
+		notified = append(notified, a)
+		return nil
+	}))
+	engine.Add("empty", WhileStatus("started", Change{Metric: nitrado.StatsCurrentPlayers, By: -100, Percent: true, Within: 10 * time.Minute}))
+	engine.Add("full", Threshold{Metric: nitrado.StatsCurrentPlayers, Value: 100, Percent: true})
+
+	players(t, server, "[[12,1622359920],[10,1622359980]]")
+	sent, err := engine.Check(context.Background())
+	require.Nil(t, err)
+	assert.Empty(t, sent)
+
+	// The server emptied while started.
+	players(t, server, "[[12,1622359920],[10,1622359980],[0,1622360040]]")
+	fail = true
+	_, err = engine.Check(context.Background())
+	assert.EqualError(t, err, `notifying "empty": discord is down`)
+	assert.Empty(t, engine.Firing())
+
+	fail = false
+	sent, err = engine.Check(context.Background())
+	require.Nil(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "empty", sent[0].Rule)
+	assert.False(t, sent[0].Resolved)
+	assert.Equal(t, "[firing] empty on service 7654321: current_players dropped from 12 to 0 within 2m0s", sent[0].String())
+	assert.Equal(t, []string{"empty"}, engine.Firing())
+
+	// Still firing, so nothing is sent.
+	sent, err = engine.Check(context.Background())
+	require.Nil(t, err)
+	assert.Empty(t, sent)
+
+	players(t, server, "[[0,1622360040],[10,1622360100]]")
+	sent, err = engine.Check(context.Background())
+	require.Nil(t, err)
+	require.Len(t, sent, 1)
+	assert.True(t, sent[0].Resolved)
+	assert.Equal(t, "[resolved] empty on service 7654321", sent[0].String())
+	assert.Empty(t, engine.Firing())
+	assert.Len(t, notified, 2)
+}
+
+// TestEngine_Check_apiError tests that an API error does not resolve firing rules.
+func TestEngine_Check_apiError(t *testing.T) {
+	server := nitradotest.NewServer()
+	defer server.Close()
+	server.AddGameServer(nitrado.Service{ID: 7654321}, nitrado.GameServer{Slots: 10})
+	client := server.Client()
+	var notified []Alert
+	engine := NewEngine(client, 7654321, NotifierFunc(func(ctx context.Context, a Alert) error {
+		notified = append(notified, a)
+		return nil
+	}))
+	engine.Add("started", RuleFunc(func(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string) {
+		return gs.Status == "started", "the server is started"
+	}))
+
+	_, err := engine.Check(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"started"}, engine.Firing())
+
+	server.Inject(nitradotest.Fault{Status: http.StatusServiceUnavailable, Message: "Service unavailable"})
+	sent, err := engine.Check(context.Background())
+	assert.EqualError(t, err, `getting game server: status "error" ("Service unavailable")`)
+	assert.Empty(t, sent)
+	assert.Equal(t, []string{"started"}, engine.Firing())
+	assert.Len(t, notified, 1)
+}
+
+// TestEngine_Firing tests that Firing() does not wait for a notification to be sent.
+func TestEngine_Firing(t *testing.T) {
+	server := nitradotest.NewServer()
+	defer server.Close()
+	server.AddGameServer(nitrado.Service{ID: 7654321}, nitrado.GameServer{Slots: 10})
+	client := server.Client()
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	engine := NewEngine(client, 7654321, NotifierFunc(func(ctx context.Context, a Alert) error {
+		close(sending)
+		<-release
+		return nil
+	}))
+	engine.Add("always", RuleFunc(func(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string) {
+		return true, "always firing"
+	}))
+
+	done := make(chan error)
+	go func() {
+		_, err := engine.Check(context.Background())
+		done <- err
+	}()
+	<-sending
+	assert.Empty(t, engine.Firing())
+	close(release)
+	require.Nil(t, <-done)
+	assert.Equal(t, []string{"always"}, engine.Firing())
+}
@@ -0,0 +1,159 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// Rule is a condition over a game server and its stats.
+type Rule interface {
+	// Evaluate returns whether the rule fires, and a description of the
+	// condition for the alert.
+	Evaluate(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string)
+}
+
+// RuleFunc adapts a function to a Rule.
+type RuleFunc func(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string)
+
+// Evaluate calls f.
+func (f RuleFunc) Evaluate(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string) {
+	return f(gs, stats)
+}
+
+// Threshold fires when a metric has been above, or below, a value for a
+// duration.
+type Threshold struct {
+	Metric nitrado.StatsMetric
+	Value  float64
+	Below  bool // Fire when the metric is below Value, rather than above it.
+
+	// Percent makes Value a percentage of the memory of the game server for
+	// memory usage, or of its slots for player counts.
+	Percent bool
+
+	// For is how long every point must have breached Value. The rule only
+	// fires once the stats span this long, and on the latest point when For
+	// is zero.
+	For time.Duration
+}
+
+// Evaluate checks the points of the metric within For of the latest point.
+func (t Threshold) Evaluate(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string) {
+	series := stats.Series(t.Metric)
+	if len(series) == 0 {
+		return false, ""
+	}
+	limit := t.Value
+	if t.Percent {
+		total := capacity(gs, t.Metric)
+		if total <= 0 {
+			return false, ""
+		}
+		limit = t.Value / 100 * total
+	}
+
+	last := series[len(series)-1]
+	from := last.Time.Add(-t.For)
+	if series[0].Time.After(from) {
+		return false, ""
+	}
+	for _, p := range series.Between(from, last.Time.Add(time.Second)) {
+		if (t.Below && p.Value >= limit) || (!t.Below && p.Value <= limit) {
+			return false, ""
+		}
+	}
+
+	direction := "above"
+	if t.Below {
+		direction = "below"
+	}
+	msg := fmt.Sprintf("%s is %s, %s %s", t.Metric, format(last.Value), direction, format(limit))
+	if t.For > 0 {
+		msg += " for " + t.For.String()
+	}
+	return true, msg
+}
+
+// Change fires when a metric rises, or drops, by an amount within a
+// duration, such as a sudden drop of the player count.
+type Change struct {
+	Metric nitrado.StatsMetric
+	By     float64 // The change which fires the rule, negative for a drop.
+
+	// Percent makes By a percentage of the value it changed from, so that a
+	// By of -100 fires when the metric drops to zero.
+	Percent bool
+
+	// Within is how far back from the latest point to look for the value it
+	// changed from. The highest value is used for drops, and the lowest for
+	// rises.
+	Within time.Duration
+}
+
+// Evaluate compares the latest point of the metric to the points within
+// Within before it.
+func (c Change) Evaluate(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string) {
+	series := stats.Series(c.Metric)
+	if len(series) < 2 || c.By == 0 {
+		return false, ""
+	}
+	last := series[len(series)-1]
+	window := series.Between(last.Time.Add(-c.Within), last.Time)
+	if len(window) == 0 {
+		return false, ""
+	}
+
+	var from nitrado.Point
+	if c.By < 0 {
+		from, _ = window.Max()
+	} else {
+		from, _ = window.Min()
+	}
+	change := last.Value - from.Value
+	if c.Percent {
+		if from.Value == 0 {
+			return false, ""
+		}
+		change = change / from.Value * 100
+	}
+	if (c.By < 0 && change > c.By) || (c.By > 0 && change < c.By) {
+		return false, ""
+	}
+
+	verb := "rose"
+	if c.By < 0 {
+		verb = "dropped"
+	}
+	return true, fmt.Sprintf("%s %s from %s to %s within %s", c.Metric, verb, format(from.Value), format(last.Value), last.Time.Sub(from.Time))
+}
+
+// WhileStatus only evaluates r while the game server has the given status,
+// such as "started".
+func WhileStatus(status string, r Rule) Rule {
+	return RuleFunc(func(gs *nitrado.GameServer, stats *nitrado.GSStats) (bool, string) {
+		if gs.Status != status {
+			return false, ""
+		}
+		return r.Evaluate(gs, stats)
+	})
+}
+
+// capacity returns the value which a percentage of the metric refers to.
+func capacity(gs *nitrado.GameServer, m nitrado.StatsMetric) float64 {
+	switch m {
+	case nitrado.StatsCPUUsage:
+		return 100
+	case nitrado.StatsMemoryUsage:
+		return float64(gs.MemoryMb)
+	case nitrado.StatsCurrentPlayers, nitrado.StatsMaxPlayers:
+		return float64(gs.Slots)
+	}
+	return 0
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
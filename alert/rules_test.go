@@ -0,0 +1,151 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+)
+
+// series returns a series with a point every minute.
+func series(values ...float64) nitrado.TimeSeries {
+	ts := make(nitrado.TimeSeries, len(values))
+	for i, v := range values {
+		ts[i] = nitrado.Point{Time: time.Unix(1622359920+int64(i)*60, 0), Value: v}
+	}
+	return ts
+}
+
+// TestThreshold_Evaluate tests the Threshold Evaluate() method.
+func TestThreshold_Evaluate(t *testing.T) {
+	gs := &nitrado.GameServer{MemoryMb: 4000, Slots: 10}
+
+	tests := []struct {
+		name   string
+		rule   Threshold
+		stats  nitrado.GSStats
+		firing bool
+		msg    string
+	}{
+		{
+			name:   "Latest point above",
+			rule:   Threshold{Metric: nitrado.StatsCPUUsage, Value: 80},
+			stats:  nitrado.GSStats{CPUUsage: series(10, 95)},
+			firing: true,
+			msg:    "cpu_usage is 95, above 80",
+		},
+		{
+			name:  "Latest point equal",
+			rule:  Threshold{Metric: nitrado.StatsCPUUsage, Value: 80},
+			stats: nitrado.GSStats{CPUUsage: series(95, 80)},
+		},
+		{
+			name:   "Percent of memory for a duration",
+			rule:   Threshold{Metric: nitrado.StatsMemoryUsage, Value: 90, Percent: true, For: 2 * time.Minute},
+			stats:  nitrado.GSStats{MemoryUsage: series(1000, 3700, 3800, 3900)},
+			firing: true,
+			msg:    "memory_usage is 3900, above 3600 for 2m0s",
+		},
+		{
+			name:  "Breach shorter than the duration",
+			rule:  Threshold{Metric: nitrado.StatsMemoryUsage, Value: 90, Percent: true, For: 3 * time.Minute},
+			stats: nitrado.GSStats{MemoryUsage: series(1000, 3700, 3800, 3900)},
+		},
+		{
+			name:  "Stats shorter than the duration",
+			rule:  Threshold{Metric: nitrado.StatsMemoryUsage, Value: 90, Percent: true, For: time.Hour},
+			stats: nitrado.GSStats{MemoryUsage: series(3700, 3800, 3900)},
+		},
+		{
+			name:   "Below",
+			rule:   Threshold{Metric: nitrado.StatsCurrentPlayers, Value: 10, Percent: true, Below: true},
+			stats:  nitrado.GSStats{CurrentPlayers: series(5, 0)},
+			firing: true,
+			msg:    "current_players is 0, below 1",
+		},
+		{
+			name:  "No stats",
+			rule:  Threshold{Metric: nitrado.StatsCPUUsage, Value: 80},
+			stats: nitrado.GSStats{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			firing, msg := tt.rule.Evaluate(gs, &tt.stats)
+			assert.Equal(t, tt.firing, firing)
+			assert.Equal(t, tt.msg, msg)
+		})
+	}
+
+	// Without the memory of the game server a percentage cannot be evaluated.
+	stats := nitrado.GSStats{MemoryUsage: series(3900)}
+	firing, _ := Threshold{Metric: nitrado.StatsMemoryUsage, Value: 90, Percent: true}.Evaluate(&nitrado.GameServer{}, &stats)
+	assert.False(t, firing)
+}
+
+// TestChange_Evaluate tests the Change Evaluate() method.
+func TestChange_Evaluate(t *testing.T) {
+	gs := &nitrado.GameServer{}
+
+	tests := []struct {
+		name   string
+		rule   Change
+		stats  nitrado.GSStats
+		firing bool
+		msg    string
+	}{
+		{
+			name:   "Drop to zero",
+			rule:   Change{Metric: nitrado.StatsCurrentPlayers, By: -100, Percent: true, Within: 5 * time.Minute},
+			stats:  nitrado.GSStats{CurrentPlayers: series(8, 12, 10, 0)},
+			firing: true,
+			msg:    "current_players dropped from 12 to 0 within 2m0s",
+		},
+		{
+			name:  "Gradual drop outside the window",
+			rule:  Change{Metric: nitrado.StatsCurrentPlayers, By: -100, Percent: true, Within: 2 * time.Minute},
+			stats: nitrado.GSStats{CurrentPlayers: series(12, 0, 0, 0)},
+		},
+		{
+			name:  "Already empty",
+			rule:  Change{Metric: nitrado.StatsCurrentPlayers, By: -100, Percent: true, Within: 5 * time.Minute},
+			stats: nitrado.GSStats{CurrentPlayers: series(0, 0)},
+		},
+		{
+			name:   "Rise",
+			rule:   Change{Metric: nitrado.StatsMemoryUsage, By: 500, Within: 10 * time.Minute},
+			stats:  nitrado.GSStats{MemoryUsage: series(2000, 2100, 2600)},
+			firing: true,
+			msg:    "memory_usage rose from 2000 to 2600 within 2m0s",
+		},
+		{
+			name:  "Small rise",
+			rule:  Change{Metric: nitrado.StatsMemoryUsage, By: 500, Within: 10 * time.Minute},
+			stats: nitrado.GSStats{MemoryUsage: series(2000, 2100, 2400)},
+		},
+		{
+			name:  "Single point",
+			rule:  Change{Metric: nitrado.StatsMemoryUsage, By: 500, Within: 10 * time.Minute},
+			stats: nitrado.GSStats{MemoryUsage: series(2000)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			firing, msg := tt.rule.Evaluate(gs, &tt.stats)
+			assert.Equal(t, tt.firing, firing)
+			assert.Equal(t, tt.msg, msg)
+		})
+	}
+}
+
+// TestWhileStatus tests the WhileStatus() function.
+func TestWhileStatus(t *testing.T) {
+	rule := WhileStatus("started", Change{Metric: nitrado.StatsCurrentPlayers, By: -100, Percent: true, Within: 5 * time.Minute})
+	stats := nitrado.GSStats{CurrentPlayers: series(12, 0)}
+
+	firing, _ := rule.Evaluate(&nitrado.GameServer{Status: "started"}, &stats)
+	assert.True(t, firing)
+	firing, _ = rule.Evaluate(&nitrado.GameServer{Status: "restarting"}, &stats)
+	assert.False(t, firing)
+}
@@ -0,0 +1,287 @@
+// Package battleye implements a client for the BattlEye RCon protocol, which
+// is used to administer DayZ and Arma servers directly over UDP.
+//
+// Protocol documentation: https://www.battleye.com/downloads/BERConProtocol.txt
+package battleye
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout   = 5 * time.Second
+	defaultKeepAlive = 30 * time.Second // The server drops clients which are silent for 45 seconds.
+)
+
+var (
+	// ErrLoginFailed is returned by Dial when the server rejects the password.
+	ErrLoginFailed = errors.New("battleye: login failed")
+	// ErrTimeout is returned when the server does not respond in time.
+	ErrTimeout = errors.New("battleye: timeout waiting for the server")
+	// ErrClosed is returned when using a Client after Close.
+	ErrClosed = errors.New("battleye: client closed")
+)
+
+// Dialer contains options for connecting to a BattlEye RCon server.
+type Dialer struct {
+	Timeout   time.Duration // How long to wait for the login and each command, defaults to 5 seconds.
+	KeepAlive time.Duration // How often to send a keep-alive packet, defaults to 30 seconds.
+
+	// OnMessage is called with every message sent by the server, such as
+	// chat and connection messages. It is called from the receiving
+	// goroutine, so it must not block.
+	OnMessage func(string)
+}
+
+// Client is a connection to a BattlEye RCon server. It is safe for
+// concurrent use.
+type Client struct {
+	conn      net.Conn
+	timeout   time.Duration
+	onMessage func(string)
+
+	mu      sync.Mutex
+	seq     byte
+	pending map[byte]*response
+	login   chan bool
+
+	lastMessage int // Sequence number of the last server message, -1 before the first.
+	done        chan struct{}
+	closeOnce   sync.Once
+	wg          sync.WaitGroup
+}
+
+// response collects the parts of the response to a command.
+type response struct {
+	parts    [][]byte
+	received int
+	ch       chan string
+}
+
+// Dial connects to the server at addr and logs in with password, using the
+// default options.
+func Dial(addr, password string) (*Client, error) {
+	var d Dialer
+	return d.Dial(addr, password)
+}
+
+// Dial connects to the server at addr and logs in with password.
+func (d *Dialer) Dial(addr, password string) (*Client, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:        conn,
+		timeout:     d.Timeout,
+		onMessage:   d.OnMessage,
+		pending:     make(map[byte]*response),
+		login:       make(chan bool, 1),
+		lastMessage: -1,
+		done:        make(chan struct{}),
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	keepAlive := d.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = defaultKeepAlive
+	}
+
+	c.wg.Add(1)
+	go c.receive()
+
+	if err := c.authenticate(password); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	c.wg.Add(1)
+	go c.keepAlive(keepAlive)
+
+	return c, nil
+}
+
+// authenticate sends the login packet and waits for the result.
+func (c *Client) authenticate(password string) error {
+	if _, err := c.conn.Write(encode(loginPacket, []byte(password))); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case ok := <-c.login:
+		if !ok {
+			return ErrLoginFailed
+		}
+		return nil
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
+
+// Command runs a command on the server and returns its response. Commands
+// which have no response, such as "say", return an empty string.
+func (c *Client) Command(command string) (string, error) {
+	c.mu.Lock()
+	seq := c.seq
+	c.seq++
+	r := &response{ch: make(chan string, 1)}
+	c.pending[seq] = r
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, seq)
+		c.mu.Unlock()
+	}()
+
+	payload := append([]byte{seq}, command...)
+	if _, err := c.conn.Write(encode(commandPacket, payload)); err != nil {
+		select {
+		case <-c.done:
+			return "", ErrClosed
+		default:
+			return "", err
+		}
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case out := <-r.ch:
+		return out, nil
+	case <-timer.C:
+		return "", ErrTimeout
+	case <-c.done:
+		return "", ErrClosed
+	}
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+		c.wg.Wait()
+	})
+	return err
+}
+
+// receive reads packets from the server until the client is closed.
+func (c *Client) receive() {
+	defer c.wg.Done()
+	buf := make([]byte, 65535)
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+			// Errors such as ICMP port unreachable are reported on reads of
+			// a UDP socket. They are not fatal, the server may come back.
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		t, payload, err := decode(buf[:n])
+		if err != nil || len(payload) == 0 {
+			continue
+		}
+		switch t {
+		case loginPacket:
+			select {
+			case c.login <- payload[0] == 0x01:
+			default:
+			}
+		case commandPacket:
+			c.handleResponse(payload[0], payload[1:])
+		case messagePacket:
+			c.handleMessage(payload[0], payload[1:])
+		}
+	}
+}
+
+// handleResponse delivers a response, or one part of a multi-packet
+// response, to the waiting command.
+func (c *Client) handleResponse(seq byte, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.pending[seq]
+	if !ok {
+		return
+	}
+
+	// Multi-packet responses start with 0x00, the number of packets and the
+	// index of this packet.
+	if len(data) >= 3 && data[0] == 0x00 {
+		total, index := int(data[1]), int(data[2])
+		if total == 0 || index >= total {
+			return
+		}
+		if r.parts == nil {
+			r.parts = make([][]byte, total)
+		}
+		if index >= len(r.parts) || r.parts[index] != nil {
+			return
+		}
+		r.parts[index] = append([]byte{}, data[3:]...)
+		r.received++
+		if r.received < len(r.parts) {
+			return
+		}
+		var out []byte
+		for _, p := range r.parts {
+			out = append(out, p...)
+		}
+		data = out
+	}
+
+	select {
+	case r.ch <- string(data):
+	default:
+	}
+}
+
+// handleMessage acknowledges a server message and passes it on. The server
+// resends messages which are not acknowledged, so repeats are dropped.
+func (c *Client) handleMessage(seq byte, data []byte) {
+	_, _ = c.conn.Write(encode(messagePacket, []byte{seq}))
+
+	c.mu.Lock()
+	repeat := c.lastMessage == int(seq)
+	c.lastMessage = int(seq)
+	c.mu.Unlock()
+
+	if !repeat && c.onMessage != nil {
+		c.onMessage(string(data))
+	}
+}
+
+// keepAlive sends an empty command periodically, so that the server does
+// not drop the connection.
+func (c *Client) keepAlive(interval time.Duration) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			seq := c.seq
+			c.seq++
+			c.mu.Unlock()
+			_, _ = c.conn.Write(encode(commandPacket, []byte{seq}))
+		}
+	}
+}
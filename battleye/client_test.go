@@ -0,0 +1,216 @@
+package battleye
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const playersOutput = "Players on server:\n" +
+	"[#] [IP Address]:[Port] [Ping] [GUID] [Name]\n" +
+	"--------------------------------------------------\n" +
+	"0   192.168.1.10:2304     31   0123456789abcdef0123456789abcdef(OK) Survivor\n" +
+	"1   192.168.1.11:2304     -1   fedcba9876543210fedcba9876543210(?) New Player (Lobby)\n" +
+	"(2 players in total)"
+
+// fakeServer is an in-process BattlEye RCon server.
+type fakeServer struct {
+	t        *testing.T
+	conn     *net.UDPConn
+	password string
+
+	mu         sync.Mutex
+	client     *net.UDPAddr
+	commands   []string
+	keepAlives int
+	acks       []byte
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.Nil(t, err)
+	s := &fakeServer{t: t, conn: conn, password: password}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+	return s
+}
+
+func (s *fakeServer) addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *fakeServer) send(t packetType, payload []byte) {
+	s.mu.Lock()
+	addr := s.client
+	s.mu.Unlock()
+	_, _ = s.conn.WriteToUDP(encode(t, payload), addr)
+}
+
+func (s *fakeServer) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		t, payload, err := decode(buf[:n])
+		if !assert.Nil(s.t, err) {
+			continue
+		}
+		s.mu.Lock()
+		s.client = addr
+		s.mu.Unlock()
+
+		switch t {
+		case loginPacket:
+			ok := byte(0x00)
+			if string(payload) == s.password {
+				ok = 0x01
+			}
+			s.send(loginPacket, []byte{ok})
+		case messagePacket:
+			s.mu.Lock()
+			s.acks = append(s.acks, payload[0])
+			s.mu.Unlock()
+		case commandPacket:
+			seq, command := payload[0], string(payload[1:])
+			s.mu.Lock()
+			if command == "" {
+				s.keepAlives++
+			} else {
+				s.commands = append(s.commands, command)
+			}
+			s.mu.Unlock()
+
+			switch command {
+			case "players":
+				// Split the response over three packets, sent out of order.
+				parts := []string{playersOutput[:60], playersOutput[60:120], playersOutput[120:]}
+				for _, i := range []int{2, 0, 1} {
+					s.send(commandPacket, append([]byte{seq, 0x00, 3, byte(i)}, parts[i]...))
+				}
+			case "version":
+				s.send(commandPacket, append([]byte{seq}, "1.234"...))
+			case "ignored":
+			default:
+				s.send(commandPacket, []byte{seq})
+			}
+		}
+	}
+}
+
+// TestDial tests logging in to a server.
+func TestDial(t *testing.T) {
+	s := newFakeServer(t, "secret")
+
+	c, err := Dial(s.addr(), "secret")
+	require.Nil(t, err)
+	require.Nil(t, c.Close())
+	assert.Nil(t, c.Close())
+
+	_, err = Dial(s.addr(), "wrong")
+	assert.ErrorIs(t, err, ErrLoginFailed)
+
+	// Nothing is listening on a closed port.
+	closed, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.Nil(t, err)
+	addr := closed.LocalAddr().String()
+	closed.Close()
+	d := Dialer{Timeout: 50 * time.Millisecond}
+	_, err = d.Dial(addr, "secret")
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+// TestClient_Command tests the Client Command() method and the command helpers.
+func TestClient_Command(t *testing.T) {
+	s := newFakeServer(t, "secret")
+	d := Dialer{Timeout: 200 * time.Millisecond}
+	c, err := d.Dial(s.addr(), "secret")
+	require.Nil(t, err)
+	defer c.Close()
+
+	out, err := c.Command("version")
+	require.Nil(t, err)
+	assert.Equal(t, "1.234", out)
+
+	players, err := c.Players()
+	require.Nil(t, err)
+	assert.Equal(t, []Player{
+		{Number: 0, IP: "192.168.1.10", Port: 2304, Ping: 31, GUID: "0123456789abcdef0123456789abcdef", Verified: true, Name: "Survivor"},
+		{Number: 1, IP: "192.168.1.11", Port: 2304, Ping: -1, GUID: "fedcba9876543210fedcba9876543210", Name: "New Player", Lobby: true},
+	}, players)
+
+	require.Nil(t, c.Say("Restart in 5 minutes"))
+	require.Nil(t, c.SayTo(1, "Welcome"))
+	require.Nil(t, c.Kick(0, "Combat logging"))
+	require.Nil(t, c.Kick(1, ""))
+
+	_, err = c.Command("ignored")
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	s.mu.Lock()
+	assert.Equal(t, []string{"version", "players", "say -1 Restart in 5 minutes", "say 1 Welcome", "kick 0 Combat logging", "kick 1", "ignored"}, s.commands)
+	s.mu.Unlock()
+
+	c.Close()
+	_, err = c.Command("version")
+	assert.Error(t, err)
+}
+
+// TestClient_Concurrent tests that concurrent commands receive their own responses.
+func TestClient_Concurrent(t *testing.T) {
+	s := newFakeServer(t, "secret")
+	c, err := Dial(s.addr(), "secret")
+	require.Nil(t, err)
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := c.Command("players")
+			assert.Nil(t, err)
+			assert.Equal(t, playersOutput, out)
+		}()
+	}
+	wg.Wait()
+}
+
+// TestClient_Messages tests that server messages are acknowledged and passed on once.
+func TestClient_Messages(t *testing.T) {
+	s := newFakeServer(t, "secret")
+	var mu sync.Mutex
+	var messages []string
+	d := Dialer{
+		KeepAlive: 20 * time.Millisecond,
+		OnMessage: func(m string) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, m)
+		},
+	}
+	c, err := d.Dial(s.addr(), "secret")
+	require.Nil(t, err)
+	defer c.Close()
+
+	s.send(messagePacket, append([]byte{0}, "Player #0 Survivor connected"...))
+	s.send(messagePacket, append([]byte{0}, "Player #0 Survivor connected"...))
+	s.send(messagePacket, append([]byte{1}, "(Global) Survivor: hello"...))
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.acks) == 3 && s.keepAlives >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "Player #0 Survivor connected|(Global) Survivor: hello", strings.Join(messages, "|"))
+	assert.Equal(t, []byte{0, 0, 1}, s.acks)
+}
@@ -0,0 +1,79 @@
+package battleye
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Player is a player listed by the "players" command.
+type Player struct {
+	Number   int // The number used to address the player in commands such as kick.
+	IP       string
+	Port     int
+	Ping     int
+	GUID     string
+	Verified bool // The GUID has been verified by BattlEye.
+	Name     string
+	Lobby    bool // The player is still in the lobby.
+}
+
+var playerLineRe = regexp.MustCompile(`^(\d+)\s+([\d.]+):(\d+)\s+(-?\d+)\s+([0-9a-fA-F-]+)\((OK|\?)\)\s+(.*?)(\s+\(Lobby\))?$`)
+
+// Players returns the players on the server.
+func (c *Client) Players() ([]Player, error) {
+	out, err := c.Command("players")
+	if err != nil {
+		return nil, err
+	}
+	return parsePlayers(out), nil
+}
+
+// parsePlayers parses the output of the "players" command, skipping the
+// header and summary lines.
+func parsePlayers(out string) []Player {
+	var players []Player
+	for _, line := range strings.Split(out, "\n") {
+		m := playerLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(m[1])
+		port, _ := strconv.Atoi(m[3])
+		ping, _ := strconv.Atoi(m[4])
+		players = append(players, Player{
+			Number:   number,
+			IP:       m[2],
+			Port:     port,
+			Ping:     ping,
+			GUID:     m[5],
+			Verified: m[6] == "OK",
+			Name:     m[7],
+			Lobby:    m[8] != "",
+		})
+	}
+	return players
+}
+
+// Say broadcasts a message to every player.
+func (c *Client) Say(message string) error {
+	_, err := c.Command("say -1 " + message)
+	return err
+}
+
+// SayTo sends a message to the player with the given number.
+func (c *Client) SayTo(player int, message string) error {
+	_, err := c.Command(fmt.Sprintf("say %d %s", player, message))
+	return err
+}
+
+// Kick kicks the player with the given number, showing them reason.
+func (c *Client) Kick(player int, reason string) error {
+	command := fmt.Sprintf("kick %d", player)
+	if reason != "" {
+		command += " " + reason
+	}
+	_, err := c.Command(command)
+	return err
+}
@@ -0,0 +1,29 @@
+package battleye
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// DialGameServer connects to the RCon port of a Nitrado GameServer, logging
+// in with its rcon-password setting, using the default options.
+func DialGameServer(gs *nitrado.GameServer) (*Client, error) {
+	var d Dialer
+	return d.DialGameServer(gs)
+}
+
+// DialGameServer connects to the RCon port of a Nitrado GameServer, logging
+// in with its rcon-password setting.
+func (d *Dialer) DialGameServer(gs *nitrado.GameServer) (*Client, error) {
+	password, _ := gs.Settings.Value("general", "rcon-password")
+	if gs.IP == "" || gs.RconPort == 0 {
+		return nil, errors.New("battleye: game server has no RCon address")
+	}
+	if password == "" {
+		return nil, errors.New("battleye: game server has no RCon password")
+	}
+	return d.Dial(net.JoinHostPort(gs.IP, strconv.Itoa(gs.RconPort)), password)
+}
@@ -0,0 +1,29 @@
+package battleye
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDialGameServer tests the DialGameServer() function.
+func TestDialGameServer(t *testing.T) {
+	s := newFakeServer(t, "secret")
+	host, port, err := net.SplitHostPort(s.addr())
+	require.Nil(t, err)
+	rconPort, _ := strconv.Atoi(port)
+
+	gs := &nitrado.GameServer{IP: host, RconPort: rconPort, Settings: nitrado.Settings{"general": {"rcon-password": "secret"}}}
+	c, err := DialGameServer(gs)
+	require.Nil(t, err)
+	c.Close()
+
+	_, err = DialGameServer(&nitrado.GameServer{IP: host, RconPort: rconPort})
+	assert.Error(t, err)
+	_, err = DialGameServer(&nitrado.GameServer{Settings: gs.Settings})
+	assert.Error(t, err)
+}
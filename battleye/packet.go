@@ -0,0 +1,45 @@
+package battleye
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// packetType is the type of a BattlEye RCon packet.
+type packetType byte
+
+// The packet types of the BattlEye RCon protocol.
+const (
+	loginPacket   packetType = 0x00
+	commandPacket packetType = 0x01
+	messagePacket packetType = 0x02
+)
+
+// headerSize is the size of the "BE", checksum and 0xFF header.
+const headerSize = 7
+
+var errInvalidPacket = errors.New("invalid BattlEye packet")
+
+// encode builds a packet of the given type. The checksum covers everything
+// after it, starting with the 0xFF byte.
+func encode(t packetType, payload []byte) []byte {
+	p := make([]byte, headerSize+1+len(payload))
+	p[0], p[1] = 'B', 'E'
+	p[6] = 0xFF
+	p[7] = byte(t)
+	copy(p[8:], payload)
+	binary.LittleEndian.PutUint32(p[2:6], crc32.ChecksumIEEE(p[6:]))
+	return p
+}
+
+// decode checks a packet and returns its type and payload.
+func decode(p []byte) (packetType, []byte, error) {
+	if len(p) < headerSize+1 || p[0] != 'B' || p[1] != 'E' || p[6] != 0xFF {
+		return 0, nil, errInvalidPacket
+	}
+	if binary.LittleEndian.Uint32(p[2:6]) != crc32.ChecksumIEEE(p[6:]) {
+		return 0, nil, errInvalidPacket
+	}
+	return packetType(p[7]), p[8:], nil
+}
@@ -0,0 +1,46 @@
+package battleye
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncode tests the encode() function.
+func TestEncode(t *testing.T) {
+	p := encode(loginPacket, []byte("password"))
+	assert.Equal(t, []byte{'B', 'E'}, p[:2])
+	assert.Equal(t, []byte{0xFF, 0x00}, p[6:8])
+	assert.Equal(t, "password", string(p[8:]))
+
+	typ, payload, err := decode(p)
+	require.Nil(t, err)
+	assert.Equal(t, loginPacket, typ)
+	assert.Equal(t, []byte("password"), payload)
+}
+
+// TestDecode tests the decode() function.
+func TestDecode(t *testing.T) {
+	valid := encode(commandPacket, []byte{0x05, 'x'})
+	corrupt := append([]byte{}, valid...)
+	corrupt[len(corrupt)-1] = 'y'
+
+	tests := []struct {
+		name    string
+		packet  []byte
+		wantErr bool
+	}{
+		{name: "Valid", packet: valid},
+		{name: "Empty payload", packet: encode(messagePacket, nil)},
+		{name: "Bad checksum", packet: corrupt, wantErr: true},
+		{name: "Too short", packet: valid[:6], wantErr: true},
+		{name: "Bad header", packet: append([]byte("XX"), valid[2:]...), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := decode(tt.packet)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
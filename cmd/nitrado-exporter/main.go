@@ -0,0 +1,41 @@
+// Command nitrado-exporter serves Prometheus metrics for the game servers of
+// a Nitrado account. The API token is read from the nitradoToken environment
+// variable.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/danstis/go-nitrado/exporter"
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	addr := flag.String("listen", ":9877", "address to serve metrics on")
+	interval := flag.Duration("interval", time.Minute, "how often to refresh metrics from the Nitrado API")
+	flag.Parse()
+
+	token := os.Getenv("nitradoToken")
+	if token == "" {
+		log.Fatal("nitradoToken is not set")
+	}
+
+	e := exporter.New(nitrado.NewClient(token))
+	e.Interval = *interval
+	e.OnError = func(err error) { log.Printf("refresh: %v", err) }
+	go func() { _ = e.Run(context.Background()) }()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+	http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	log.Printf("serving metrics on %s/metrics", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -0,0 +1,316 @@
+// Package admlog parses DayZ admin logs (.ADM files) into typed events.
+//
+// The events written to an admin log depend on the server settings. Hits are
+// only logged for players when adminLogPlayerHitsOnly is enabled, placement
+// of items needs adminLogPlacement and base building needs
+// adminLogBuildActions. The periodic player list needs adminLogPlayerList.
+package admlog
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+// The types of event found in admin logs.
+const (
+	Connect     EventType = "connect"
+	Disconnect  EventType = "disconnect"
+	Kill        EventType = "kill"        // A player was killed by something.
+	Hit         EventType = "hit"         // A player was hurt by something.
+	Death       EventType = "death"       // A player died, usually logged with their stats.
+	Suicide     EventType = "suicide"     // A player committed suicide.
+	Unconscious EventType = "unconscious" // A player was knocked unconscious.
+	Conscious   EventType = "conscious"   // A player regained consciousness.
+	Build       EventType = "build"       // A player placed, built, dismantled or folded something.
+	PlayerList  EventType = "playerlist"  // A player listed in the periodic player list.
+	Chat        EventType = "chat"
+	Other       EventType = "other" // A line that was not recognised.
+)
+
+// Position is a location in the game world in meters. X and Y are the map
+// coordinates and Z is the height.
+type Position struct {
+	X, Y, Z float64
+}
+
+// Player identifies a player in an event.
+type Player struct {
+	Name string
+	ID   string
+	Pos  *Position // The position of the player, when logged.
+	HP   *float64  // The health of the player, only logged for hits.
+	Dead bool      // Set when the player was logged as dead.
+}
+
+// Event is a single line of an admin log.
+type Event struct {
+	Time time.Time
+	Type EventType
+
+	// Player is the subject of the event, such as the player who connected,
+	// was killed, or built something.
+	Player Player
+
+	// Attacker is the player responsible for a kill or hit. When the cause
+	// was not a player, such as an infected or a fall, Attacker is nil and
+	// Source holds the logged cause.
+	Attacker *Player
+	Source   string
+
+	Weapon   string  // Weapon or tool used.
+	Distance float64 // Distance of a kill or hit in meters.
+	BodyPart string  // Body part of a hit, e.g. "Head".
+	Damage   float64 // Damage dealt by a hit.
+	Ammo     string  // Ammunition or damage type of a hit, e.g. "Bullet_556x45".
+
+	Action string // Build action, e.g. "placed" or "built".
+	Object string // The object of a build action, e.g. "Fence Kit".
+
+	Message string // The text of a chat message, or the unrecognised text of an Other event.
+
+	Raw string // The original line.
+
+	// NoDate is set when the date of the log was not known, see
+	// Parser.SetStart. Time then holds the time of day on January 1st of
+	// year 0, and days counted from it.
+	NoDate bool
+}
+
+const playerPattern = `Player "([^"]*)"\s*(\(DEAD\))?\s*\(id=([^\s)]*)(?:\s+pos=<([^>]*)>)?\)(?:\[HP: ([\d.]+)\])?`
+
+var (
+	clockRe  = regexp.MustCompile(`^(\d{1,2}):(\d{2}):(\d{2}) \| (.*)$`)
+	headerRe = regexp.MustCompile(`AdminLog started on (\d{4}-\d{2}-\d{2}) at (\d{1,2}:\d{2}:\d{2})`)
+	playerRe = regexp.MustCompile(`^` + playerPattern)
+	chatRe   = regexp.MustCompile(`^Chat\("([^"]*)"\s*\(id=([^\s)]*)\)\): (.*)$`)
+	// Connections are logged before the player has a position, with the id after the action.
+	connectRe = regexp.MustCompile(`^Player "([^"]*)"\s*is connected \(id=([^\s)]*)\)$`)
+
+	killRe  = regexp.MustCompile(`^killed by (.+?)(?: with (.+?))?(?: from ([\d.]+) meters)?$`)
+	hitRe   = regexp.MustCompile(`^hit by (.+?)(?: into (\w+)\((\d+)\))?(?: for ([\d.]+) damage)?(?: \(([^)]*)\))?(?: with (.+?))?(?: from ([\d.]+) meters)?$`)
+	buildRe = regexp.MustCompile(`^(?i)(placed|built|dismantled|folded|raised|lowered|mounted|unmounted|repaired|packed)\s+(.+?)(?: with (.+))?$`)
+
+	playerListStartRe = regexp.MustCompile(`^##### PlayerList log: \d+ players?`)
+)
+
+// Parser turns lines of an admin log into events. It keeps track of the date
+// from the "AdminLog started" header, as each line only records the time of
+// day. A log read from partway through has no header, so its start must be
+// set with SetStart, otherwise events are marked with NoDate. The zero value
+// is ready to use and produces times in UTC.
+type Parser struct {
+	// Location is the time zone of the server, defaults to UTC.
+	Location *time.Location
+
+	date       time.Time
+	dated      bool   // Set once the date of the log is known.
+	file       string // The log file being streamed.
+	last       time.Duration
+	playerList bool
+}
+
+// SetStart sets the time the log was started, like the "AdminLog started"
+// header does, for example from the time in the name of the log file. Lines
+// move on to the next day whenever the clock goes backwards, so a log that
+// is picked up more than a day after it was started is dated too early.
+func (p *Parser) SetStart(t time.Time) {
+	t = t.In(p.location())
+	p.date = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.location())
+	p.dated = true
+	p.last = t.Sub(p.date)
+}
+
+// ParseLine parses a single line. It returns false for lines which do not
+// describe an event, such as headers, separators and blank lines.
+func (p *Parser) ParseLine(line string) (Event, bool) {
+	line = strings.TrimRight(line, "\r\n")
+
+	if m := headerRe.FindStringSubmatch(line); m != nil {
+		p.setDate(m[1], m[2])
+		return Event{}, false
+	}
+
+	m := clockRe.FindStringSubmatch(line)
+	if m == nil {
+		return Event{}, false
+	}
+	e := Event{Time: p.timeOf(m[1], m[2], m[3]), NoDate: !p.dated, Raw: line}
+	text := strings.TrimSpace(m[4])
+
+	switch {
+	case playerListStartRe.MatchString(text):
+		p.playerList = true
+		return Event{}, false
+	case strings.HasPrefix(text, "#####"):
+		p.playerList = false
+		return Event{}, false
+	}
+
+	if cm := chatRe.FindStringSubmatch(text); cm != nil {
+		e.Type = Chat
+		e.Player = Player{Name: cm[1], ID: cm[2]}
+		e.Message = cm[3]
+		return e, true
+	}
+
+	if cm := connectRe.FindStringSubmatch(text); cm != nil {
+		e.Type = Connect
+		e.Player = Player{Name: cm[1], ID: cm[2]}
+		return e, true
+	}
+
+	player, rest, ok := parsePlayer(text)
+	if !ok {
+		e.Type = Other
+		e.Message = text
+		return e, true
+	}
+	e.Player = player
+	p.parseAction(&e, strings.TrimSpace(rest))
+
+	return e, true
+}
+
+// parseAction fills in e from the text following the subject player.
+func (p *Parser) parseAction(e *Event, rest string) {
+	switch {
+	case rest == "" && p.playerList:
+		e.Type = PlayerList
+	case rest == "is connected":
+		e.Type = Connect
+	case rest == "has been disconnected":
+		e.Type = Disconnect
+	case rest == "committed suicide":
+		e.Type = Suicide
+	case rest == "is unconscious":
+		e.Type = Unconscious
+	case rest == "regained consciousness":
+		e.Type = Conscious
+	case strings.HasPrefix(rest, "died") || rest == "bled out":
+		e.Type = Death
+		e.Message = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(rest, "died"), "."))
+	case killRe.MatchString(rest):
+		m := killRe.FindStringSubmatch(rest)
+		e.Type = Kill
+		e.Attacker, e.Source = parseCause(m[1])
+		e.Weapon = m[2]
+		e.Distance = parseFloat(m[3])
+	case hitRe.MatchString(rest):
+		m := hitRe.FindStringSubmatch(rest)
+		e.Type = Hit
+		e.Attacker, e.Source = parseCause(m[1])
+		e.BodyPart = m[2]
+		e.Damage = parseFloat(m[4])
+		e.Ammo = m[5]
+		e.Weapon = m[6]
+		e.Distance = parseFloat(m[7])
+	case buildRe.MatchString(rest):
+		m := buildRe.FindStringSubmatch(rest)
+		e.Type = Build
+		e.Action = strings.ToLower(m[1])
+		e.Object = m[2]
+		e.Weapon = m[3]
+	default:
+		e.Type = Other
+		e.Message = rest
+	}
+}
+
+// parsePlayer parses a player reference at the start of text, returning the
+// remaining text.
+func parsePlayer(text string) (Player, string, bool) {
+	loc := playerRe.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return Player{}, text, false
+	}
+
+	sub := func(i int) string {
+		if loc[2*i] < 0 {
+			return ""
+		}
+		return text[loc[2*i]:loc[2*i+1]]
+	}
+	player := Player{
+		Name: sub(1),
+		Dead: sub(2) != "",
+		ID:   sub(3),
+		Pos:  parsePosition(sub(4)),
+	}
+	if hp := sub(5); hp != "" {
+		v := parseFloat(hp)
+		player.HP = &v
+	}
+
+	return player, text[loc[1]:], true
+}
+
+// parseCause parses the cause of a kill or hit, which is either a player or
+// a description such as "Infected" or "FallDamage".
+func parseCause(text string) (*Player, string) {
+	if player, rest, ok := parsePlayer(text); ok && strings.TrimSpace(rest) == "" {
+		return &player, ""
+	}
+	return nil, strings.TrimSpace(text)
+}
+
+// parsePosition parses a position such as "4500.5, 10200.3, 200.1".
+func parsePosition(s string) *Position {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return nil
+	}
+	var v [3]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil
+		}
+		v[i] = f
+	}
+	return &Position{X: v[0], Y: v[1], Z: v[2]}
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// setDate records the date and time that the log was started.
+func (p *Parser) setDate(date, clock string) {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", date+" "+clock, p.location())
+	if err != nil {
+		return
+	}
+	p.SetStart(t)
+}
+
+// timeOf returns the time of a line, moving on to the next day when the
+// clock goes backwards.
+func (p *Parser) timeOf(h, m, s string) time.Time {
+	hours, _ := strconv.Atoi(h)
+	minutes, _ := strconv.Atoi(m)
+	seconds, _ := strconv.Atoi(s)
+	clock := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+
+	if p.date.IsZero() {
+		p.date = time.Date(0, 1, 1, 0, 0, 0, 0, p.location())
+	}
+	if clock < p.last {
+		p.date = p.date.AddDate(0, 0, 1)
+	}
+	p.last = clock
+
+	return p.date.Add(clock)
+}
+
+func (p *Parser) location() *time.Location {
+	if p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
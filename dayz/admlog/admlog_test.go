@@ -0,0 +1,170 @@
+package admlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float(f float64) *float64 { return &f }
+
+// TestParser_ParseLine tests parsing individual admin log lines.
+func TestParser_ParseLine(t *testing.T) {
+	victim := Player{Name: "Survivor", ID: "qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=", Pos: &Position{X: 4660.1, Y: 10190.4, Z: 339.5}}
+	killer := Player{Name: "Bandit 99", ID: "Hh5Jj1Mh0jq9Oq8EoG6kGzqN2ugDWS0V1xY7XbX4uH8=", Pos: &Position{X: 4710.8, Y: 10120.1, Z: 344.0}}
+	deadVictim := victim
+	deadVictim.Dead = true
+	hurtVictim := victim
+	hurtVictim.HP = float(72.5)
+
+	tests := []struct {
+		name   string
+		line   string
+		want   Event
+		wantOK bool
+	}{
+		{
+			name:   "Connect",
+			line:   `12:00:04 | Player "Survivor" is connected (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=)`,
+			want:   Event{Type: Connect, Player: Player{Name: "Survivor", ID: "qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c="}},
+			wantOK: true,
+		},
+		{
+			name:   "Disconnect",
+			line:   `12:00:04 | Player "Survivor"(id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=) has been disconnected`,
+			want:   Event{Type: Disconnect, Player: Player{Name: "Survivor", ID: "qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c="}},
+			wantOK: true,
+		},
+		{
+			name:   "Kill by player",
+			line:   `12:00:04 | Player "Survivor" (DEAD) (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) killed by Player "Bandit 99" (id=Hh5Jj1Mh0jq9Oq8EoG6kGzqN2ugDWS0V1xY7XbX4uH8= pos=<4710.8, 10120.1, 344.0>) with Mosin 91/30 from 120.5 meters `,
+			want:   Event{Type: Kill, Player: deadVictim, Attacker: &killer, Weapon: "Mosin 91/30", Distance: 120.5},
+			wantOK: true,
+		},
+		{
+			name:   "Melee kill",
+			line:   `12:00:04 | Player "Survivor" (DEAD) (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) killed by Player "Bandit 99" (id=Hh5Jj1Mh0jq9Oq8EoG6kGzqN2ugDWS0V1xY7XbX4uH8= pos=<4710.8, 10120.1, 344.0>) with Fireaxe`,
+			want:   Event{Type: Kill, Player: deadVictim, Attacker: &killer, Weapon: "Fireaxe"},
+			wantOK: true,
+		},
+		{
+			name:   "Kill by infected",
+			line:   `12:00:04 | Player "Survivor" (DEAD) (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) killed by ZmbM_HermitSkinny_Beige`,
+			want:   Event{Type: Kill, Player: deadVictim, Source: "ZmbM_HermitSkinny_Beige"},
+			wantOK: true,
+		},
+		{
+			name:   "Hit by player",
+			line:   `12:00:04 | Player "Survivor" (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>)[HP: 72.5] hit by Player "Bandit 99" (id=Hh5Jj1Mh0jq9Oq8EoG6kGzqN2ugDWS0V1xY7XbX4uH8= pos=<4710.8, 10120.1, 344.0>) into Head(0) for 27.5 damage (Bullet_762x54) with Mosin 91/30 from 120.5 meters `,
+			want:   Event{Type: Hit, Player: hurtVictim, Attacker: &killer, BodyPart: "Head", Damage: 27.5, Ammo: "Bullet_762x54", Weapon: "Mosin 91/30", Distance: 120.5},
+			wantOK: true,
+		},
+		{
+			name:   "Fall damage",
+			line:   `12:00:04 | Player "Survivor" (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>)[HP: 72.5] hit by FallDamage`,
+			want:   Event{Type: Hit, Player: hurtVictim, Source: "FallDamage"},
+			wantOK: true,
+		},
+		{
+			name:   "Died with stats",
+			line:   `12:00:04 | Player "Survivor" (DEAD) (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) died. Stats> Water: 1341.74 Energy: 1822.12 Bleed sources: 2`,
+			want:   Event{Type: Death, Player: deadVictim, Message: "Stats> Water: 1341.74 Energy: 1822.12 Bleed sources: 2"},
+			wantOK: true,
+		},
+		{
+			name:   "Bled out",
+			line:   `12:00:04 | Player "Survivor" (DEAD) (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) bled out`,
+			want:   Event{Type: Death, Player: deadVictim, Message: "bled out"},
+			wantOK: true,
+		},
+		{
+			name:   "Placement",
+			line:   `12:00:04 | Player "Survivor" (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) placed Fence Kit`,
+			want:   Event{Type: Build, Player: victim, Action: "placed", Object: "Fence Kit"},
+			wantOK: true,
+		},
+		{
+			name:   "Build action",
+			line:   `12:00:04 | Player "Survivor" (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) Built Wall on Fence with Hammer`,
+			want:   Event{Type: Build, Player: victim, Action: "built", Object: "Wall on Fence", Weapon: "Hammer"},
+			wantOK: true,
+		},
+		{
+			name:   "Chat",
+			line:   `12:00:04 | Chat("Survivor"(id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=)): hello (friend)`,
+			want:   Event{Type: Chat, Player: Player{Name: "Survivor", ID: "qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c="}, Message: "hello (friend)"},
+			wantOK: true,
+		},
+		{
+			name:   "Unknown player action",
+			line:   `12:00:04 | Player "Survivor" (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c= pos=<4660.1, 10190.4, 339.5>) performed Emote`,
+			want:   Event{Type: Other, Player: victim, Message: "performed Emote"},
+			wantOK: true,
+		},
+		{
+			name:   "Unknown line",
+			line:   `12:00:04 | Server restart in 5 minutes`,
+			want:   Event{Type: Other, Message: "Server restart in 5 minutes"},
+			wantOK: true,
+		},
+		{
+			name: "Separator",
+			line: `******************************************************************************`,
+		},
+		{
+			name: "Blank",
+			line: ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Parser
+			got, ok := p.ParseLine(tt.line)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			tt.want.Time = time.Date(0, 1, 1, 12, 0, 4, 0, time.UTC)
+			tt.want.NoDate = true
+			tt.want.Raw = tt.line
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestParser_Time tests that the date is taken from the header and rolls over at midnight.
+func TestParser_Time(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	p := Parser{Location: loc}
+
+	_, ok := p.ParseLine("AdminLog started on 2021-07-31 at 23:55:02")
+	assert.False(t, ok)
+
+	e, _ := p.ParseLine("23:59:59 | Server restart in 5 minutes")
+	assert.Equal(t, time.Date(2021, 7, 31, 23, 59, 59, 0, loc), e.Time)
+
+	e, _ = p.ParseLine("00:00:01 | Server restart in 5 minutes")
+	assert.Equal(t, time.Date(2021, 8, 1, 0, 0, 1, 0, loc), e.Time)
+	assert.False(t, e.NoDate)
+}
+
+// TestParser_SetStart tests dating a log which is read from partway through.
+func TestParser_SetStart(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	p := Parser{Location: loc}
+
+	e, _ := p.ParseLine("23:59:59 | Server restart in 5 minutes")
+	assert.True(t, e.NoDate)
+	assert.Equal(t, 0, e.Time.Year())
+
+	p = Parser{Location: loc}
+	p.SetStart(time.Date(2021, 7, 31, 13, 55, 2, 0, time.UTC))
+	e, _ = p.ParseLine("23:59:59 | Server restart in 5 minutes")
+	assert.False(t, e.NoDate)
+	assert.Equal(t, time.Date(2021, 7, 31, 23, 59, 59, 0, loc), e.Time)
+
+	e, _ = p.ParseLine("00:00:01 | Server restart in 5 minutes")
+	assert.Equal(t, time.Date(2021, 8, 1, 0, 0, 1, 0, loc), e.Time)
+}
@@ -0,0 +1,46 @@
+package admlog
+
+import (
+	"bufio"
+	"io"
+)
+
+// Reader reads events from an admin log.
+type Reader struct {
+	Parser
+	scanner *bufio.Scanner
+}
+
+// NewReader returns a Reader that reads events from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(r)}
+}
+
+// Read returns the next event in the log. It returns io.EOF when there are no
+// more events.
+func (r *Reader) Read() (Event, error) {
+	for r.scanner.Scan() {
+		if e, ok := r.ParseLine(r.scanner.Text()); ok {
+			return e, nil
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// ReadAll reads all of the remaining events from the log.
+func (r *Reader) ReadAll() ([]Event, error) {
+	var events []Event
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+}
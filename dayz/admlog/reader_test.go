@@ -0,0 +1,58 @@
+package admlog
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadAll tests reading all events from a log file.
+func TestReader_ReadAll(t *testing.T) {
+	f, err := os.Open("testdata/DayZServer_x64_2021_07_31.ADM")
+	require.Nil(t, err)
+	defer f.Close()
+
+	events, err := NewReader(f).ReadAll()
+	require.Nil(t, err)
+
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []EventType{
+		Connect, Connect, Connect, Hit, Kill, Death, Build, Build,
+		PlayerList, PlayerList,
+		Hit, Unconscious, Conscious, Chat, Kill, Suicide, Disconnect, Build, Other,
+	}, types)
+
+	kill := events[4]
+	assert.Equal(t, time.Date(2021, 7, 31, 23, 58, 15, 0, time.UTC), kill.Time)
+	assert.Equal(t, "Survivor", kill.Player.Name)
+	assert.True(t, kill.Player.Dead)
+	require.NotNil(t, kill.Attacker)
+	assert.Equal(t, "Bandit 99", kill.Attacker.Name)
+	assert.Equal(t, "Hh5Jj1Mh0jq9Oq8EoG6kGzqN2ugDWS0V1xY7XbX4uH8=", kill.Attacker.ID)
+	assert.Equal(t, &Position{X: 4710.8, Y: 10120.1, Z: 344.0}, kill.Attacker.Pos)
+	assert.Equal(t, "M4-A1", kill.Weapon)
+	assert.Equal(t, 86.2316, kill.Distance)
+
+	// Events after midnight are on the next day.
+	assert.Equal(t, time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), events[8].Time)
+
+	dismantle := events[17]
+	assert.Equal(t, "dismantled", dismantle.Action)
+	assert.Equal(t, "Upper Wall from Fence", dismantle.Object)
+	assert.Equal(t, "Hacksaw", dismantle.Weapon)
+}
+
+// TestReader_Read tests reading from an empty log.
+func TestReader_Read(t *testing.T) {
+	r := NewReader(strings.NewReader("******\nAdminLog started on 2021-07-31 at 23:55:02\n"))
+	_, err := r.Read()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,40 @@
+package admlog
+
+import (
+	"context"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// Stream parses the lines from a nitrado.LogTailer and sends the resulting
+// events to events, until lines is closed or ctx is cancelled. When the lines
+// move on to another log file, the start of the log is set from the time in
+// its name, so that events are dated even when the tailer starts partway
+// through the file. Stream does not close events.
+func (p *Parser) Stream(ctx context.Context, lines <-chan nitrado.LogLine, events chan<- Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if line.Path != p.file {
+				p.file = line.Path
+				if !line.Started.IsZero() {
+					p.SetStart(line.Started)
+				}
+			}
+			e, ok := p.ParseLine(line.Text)
+			if !ok {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case events <- e:
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package admlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParser_Stream tests parsing events from a channel of log lines.
+func TestParser_Stream(t *testing.T) {
+	lines := make(chan nitrado.LogLine, 3)
+	lines <- nitrado.LogLine{Text: "AdminLog started on 2021-07-31 at 23:55:02"}
+	lines <- nitrado.LogLine{Text: `23:55:10 | Player "Survivor" is connected (id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=)`}
+	lines <- nitrado.LogLine{Text: `23:56:10 | Player "Survivor"(id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=) has been disconnected`}
+	close(lines)
+
+	events := make(chan Event, 3)
+	var p Parser
+	require.Nil(t, p.Stream(context.Background(), lines, events))
+	close(events)
+
+	var got []EventType
+	for e := range events {
+		got = append(got, e.Type)
+	}
+	assert.Equal(t, []EventType{Connect, Disconnect}, got)
+
+	// A log followed from partway through is dated from the name of the file.
+	lines = make(chan nitrado.LogLine, 1)
+	lines <- nitrado.LogLine{Path: "/logs/a.ADM", Started: time.Date(2021, 7, 31, 23, 55, 2, 0, time.UTC), Text: `00:10:00 | Player "Survivor"(id=qbuSbkv9T1ccS0b9ePHEOf3hXEK3nhRQ_yG3iu_Kw6c=) has been disconnected`}
+	close(lines)
+	events = make(chan Event, 1)
+	require.Nil(t, new(Parser).Stream(context.Background(), lines, events))
+	e := <-events
+	assert.False(t, e.NoDate)
+	assert.Equal(t, time.Date(2021, 8, 1, 0, 10, 0, 0, time.UTC), e.Time)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.Stream(ctx, make(chan nitrado.LogLine), events), context.Canceled)
+}
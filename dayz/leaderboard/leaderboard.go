@@ -0,0 +1,324 @@
+// Package leaderboard aggregates DayZ admin log events into per-player
+// statistics, a kill feed and leaderboards. The aggregated state is kept in a
+// Store so that it survives restarts of both the game server and the process
+// doing the aggregation.
+package leaderboard
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/danstis/go-nitrado/dayz/admlog"
+)
+
+// deathWindow is the time within which repeated death events for a player
+// are treated as the same death, as a kill is followed by a "died" line.
+const deathWindow = 10 * time.Second
+
+// defaultFeedSize is used when Aggregator.FeedSize is not set.
+const defaultFeedSize = 50
+
+// Stats contains the aggregated statistics of a single player.
+type Stats struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Kills       int           `json:"kills"`
+	Deaths      int           `json:"deaths"`
+	LongestKill float64       `json:"longest_kill"` // Distance of the longest kill in meters.
+	PlayTime    time.Duration `json:"play_time"`    // Total time of completed sessions.
+	LastSeen    time.Time     `json:"last_seen"`
+	LastDeath   time.Time     `json:"last_death,omitempty"`
+}
+
+// KD returns the kill/death ratio of the player. Players without deaths
+// return their number of kills.
+func (s Stats) KD() float64 {
+	if s.Deaths == 0 {
+		return float64(s.Kills)
+	}
+	return float64(s.Kills) / float64(s.Deaths)
+}
+
+// Kill is an entry in the kill feed.
+type Kill struct {
+	Time     time.Time `json:"time"`
+	KillerID string    `json:"killer_id,omitempty"`
+	Killer   string    `json:"killer"` // Player name, or the logged cause when not killed by a player.
+	VictimID string    `json:"victim_id"`
+	Victim   string    `json:"victim"`
+	Weapon   string    `json:"weapon,omitempty"`
+	Distance float64   `json:"distance,omitempty"`
+}
+
+// State is the aggregated data persisted in a Store.
+type State struct {
+	Players   map[string]*Stats    `json:"players"`
+	Online    map[string]time.Time `json:"online"` // Start of the current session of each online player.
+	Feed      []Kill               `json:"feed"`
+	LastEvent time.Time            `json:"last_event"`
+	Seen      map[string]int       `json:"seen,omitempty"` // Number of times each event at LastEvent was added, by eventKey.
+}
+
+// SortKey selects the statistic a leaderboard is ordered by.
+type SortKey string
+
+// The statistics leaderboards can be ordered by.
+const (
+	ByKills       SortKey = "kills"
+	ByDeaths      SortKey = "deaths"
+	ByKD          SortKey = "kd"
+	ByLongestKill SortKey = "longest_kill"
+	ByPlayTime    SortKey = "play_time"
+)
+
+// Aggregator maintains statistics from a stream of admin log events. It is
+// safe for concurrent use.
+type Aggregator struct {
+	// FeedSize is the number of kills kept in the kill feed, defaults to 50.
+	FeedSize int
+
+	store    Store
+	mu       sync.Mutex
+	state    State
+	replayed map[string]int // Number of times each event at LastEvent was added since New.
+}
+
+// New returns an Aggregator which loads its state from, and saves it to, store.
+func New(store Store) (*Aggregator, error) {
+	state, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state.Players == nil {
+		state.Players = make(map[string]*Stats)
+	}
+	if state.Online == nil {
+		state.Online = make(map[string]time.Time)
+	}
+	if state.Seen == nil {
+		state.Seen = make(map[string]int)
+	}
+
+	return &Aggregator{store: store, state: state, replayed: make(map[string]int)}, nil
+}
+
+// Add updates the statistics with an event. Events older than the last
+// event seen, and events at its time which were already added before a
+// restart, are ignored, so replaying a log after a restart does not count
+// anything twice. Events without a date, see admlog.Event.NoDate, cannot be
+// compared with the last event and are always added.
+func (a *Aggregator) Add(e admlog.Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !e.NoDate && !a.fresh(e) {
+		return
+	}
+
+	switch e.Type {
+	case admlog.Connect:
+		a.player(e.Player, e.Time)
+		if _, online := a.state.Online[e.Player.ID]; !online {
+			a.state.Online[e.Player.ID] = e.Time
+		}
+	case admlog.Disconnect:
+		a.player(e.Player, e.Time)
+		a.endSession(e.Player.ID, e.Time)
+	case admlog.Kill:
+		a.kill(e)
+	case admlog.Suicide, admlog.Death:
+		a.death(a.player(e.Player, e.Time), e.Time)
+	case admlog.PlayerList:
+		// Players still listed after a restart of the aggregator are online.
+		a.player(e.Player, e.Time)
+		if _, online := a.state.Online[e.Player.ID]; !online {
+			a.state.Online[e.Player.ID] = e.Time
+		}
+	default:
+		if e.Player.ID != "" {
+			a.player(e.Player, e.Time)
+		}
+	}
+}
+
+// fresh reports whether e was not added before, moving LastEvent on to its
+// time. The caller holds a.mu.
+func (a *Aggregator) fresh(e admlog.Event) bool {
+	if e.Time.Before(a.state.LastEvent) {
+		return false
+	}
+	if e.Time.After(a.state.LastEvent) {
+		a.state.LastEvent = e.Time
+		a.state.Seen = make(map[string]int)
+		a.replayed = make(map[string]int)
+	}
+	// An event may legitimately be logged twice in the same second, so the
+	// events at LastEvent are counted rather than only remembered.
+	key := eventKey(e)
+	a.replayed[key]++
+	if a.replayed[key] <= a.state.Seen[key] {
+		return false
+	}
+	a.state.Seen[key]++
+	return true
+}
+
+// EndSessions ends the sessions of every online player at t, for example
+// when the server is restarted and players are not logged as disconnected.
+func (a *Aggregator) EndSessions(t time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id := range a.state.Online {
+		a.endSession(id, t)
+	}
+}
+
+// Save writes the current state to the store.
+func (a *Aggregator) Save() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.store.Save(a.state)
+}
+
+// Player returns the statistics of the player with the given ID.
+func (a *Aggregator) Player(id string) (Stats, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, ok := a.state.Players[id]
+	if !ok {
+		return Stats{}, false
+	}
+	return *s, true
+}
+
+// Feed returns the most recent kills, newest first.
+func (a *Aggregator) Feed() []Kill {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	feed := make([]Kill, len(a.state.Feed))
+	for i, k := range a.state.Feed {
+		feed[len(feed)-1-i] = k
+	}
+	return feed
+}
+
+// Leaderboard returns up to n players ordered by the given statistic, highest
+// first. Play time includes the current session of online players as of now.
+// A non-positive n returns every player.
+func (a *Aggregator) Leaderboard(by SortKey, n int, now time.Time) []Stats {
+	a.mu.Lock()
+	board := make([]Stats, 0, len(a.state.Players))
+	for id, s := range a.state.Players {
+		stats := *s
+		if start, online := a.state.Online[id]; online && now.After(start) {
+			stats.PlayTime += now.Sub(start)
+		}
+		board = append(board, stats)
+	}
+	a.mu.Unlock()
+
+	value := func(s Stats) float64 {
+		switch by {
+		case ByDeaths:
+			return float64(s.Deaths)
+		case ByKD:
+			return s.KD()
+		case ByLongestKill:
+			return s.LongestKill
+		case ByPlayTime:
+			return float64(s.PlayTime)
+		default:
+			return float64(s.Kills)
+		}
+	}
+	sort.Slice(board, func(i, j int) bool {
+		vi, vj := value(board[i]), value(board[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return board[i].Name < board[j].Name
+	})
+
+	if n > 0 && len(board) > n {
+		board = board[:n]
+	}
+	return board
+}
+
+// player returns the stats for p, creating them if needed and recording the
+// latest name.
+func (a *Aggregator) player(p admlog.Player, t time.Time) *Stats {
+	s, ok := a.state.Players[p.ID]
+	if !ok {
+		s = &Stats{ID: p.ID}
+		a.state.Players[p.ID] = s
+	}
+	if p.Name != "" {
+		s.Name = p.Name
+	}
+	s.LastSeen = t
+	return s
+}
+
+// kill records a kill event for both the victim and the killer.
+func (a *Aggregator) kill(e admlog.Event) {
+	victim := a.player(e.Player, e.Time)
+	a.death(victim, e.Time)
+
+	k := Kill{Time: e.Time, Killer: e.Source, VictimID: victim.ID, Victim: victim.Name, Weapon: e.Weapon, Distance: e.Distance}
+	if e.Attacker != nil {
+		killer := a.player(*e.Attacker, e.Time)
+		k.KillerID, k.Killer = killer.ID, killer.Name
+		// Killing yourself only counts as a death.
+		if killer != victim {
+			killer.Kills++
+			if e.Distance > killer.LongestKill {
+				killer.LongestKill = e.Distance
+			}
+		}
+	}
+
+	size := a.FeedSize
+	if size <= 0 {
+		size = defaultFeedSize
+	}
+	a.state.Feed = append(a.state.Feed, k)
+	if len(a.state.Feed) > size {
+		a.state.Feed = a.state.Feed[len(a.state.Feed)-size:]
+	}
+}
+
+// death counts a death, unless one was already counted for the player
+// within deathWindow.
+func (a *Aggregator) death(s *Stats, t time.Time) {
+	if !s.LastDeath.IsZero() && t.Sub(s.LastDeath) < deathWindow {
+		return
+	}
+	s.Deaths++
+	s.LastDeath = t
+}
+
+// eventKey identifies an event among the events logged at the same time.
+func eventKey(e admlog.Event) string {
+	if e.Raw != "" {
+		return e.Raw
+	}
+	key := string(e.Type) + "|" + e.Player.ID + "|" + e.Source
+	if e.Attacker != nil {
+		key += "|" + e.Attacker.ID
+	}
+	return key
+}
+
+// endSession adds the current session of the player to their play time.
+func (a *Aggregator) endSession(id string, t time.Time) {
+	start, online := a.state.Online[id]
+	if !online {
+		return
+	}
+	delete(a.state.Online, id)
+	if s, ok := a.state.Players[id]; ok && t.After(start) {
+		s.PlayTime += t.Sub(start)
+	}
+}
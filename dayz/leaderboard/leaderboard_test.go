@@ -0,0 +1,188 @@
+package leaderboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/dayz/admlog"
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLog = `AdminLog started on 2021-07-31 at 20:00:00
+20:00:10 | Player "Alice" is connected (id=alice=)
+20:00:20 | Player "Bob" is connected (id=bob=)
+20:00:30 | Player "Carol" is connected (id=carol=)
+20:10:00 | Player "Bob" (DEAD) (id=bob= pos=<1.0, 2.0, 3.0>) killed by Player "Alice" (id=alice= pos=<4.0, 5.0, 6.0>) with M4-A1 from 86.5 meters
+20:10:00 | Player "Bob" (DEAD) (id=bob= pos=<1.0, 2.0, 3.0>) died. Stats> Water: 1341.74 Energy: 1822.12 Bleed sources: 2
+20:20:00 | Player "Carol" (DEAD) (id=carol= pos=<1.0, 2.0, 3.0>) killed by Player "Alice" (id=alice= pos=<4.0, 5.0, 6.0>) with Mosin 91/30 from 312.25 meters
+20:25:00 | Player "Alice" (DEAD) (id=alice= pos=<1.0, 2.0, 3.0>) killed by ZmbM_HermitSkinny_Beige
+20:30:00 | Player "Bob" (id=bob= pos=<1.0, 2.0, 3.0>) committed suicide
+20:40:20 | Player "Bob"(id=bob=) has been disconnected
+`
+
+// events parses an admin log into events.
+func events(t *testing.T, log string) []admlog.Event {
+	t.Helper()
+	events, err := admlog.NewReader(strings.NewReader(log)).ReadAll()
+	require.Nil(t, err)
+	return events
+}
+
+// TestAggregator tests aggregating statistics from admin log events.
+func TestAggregator(t *testing.T) {
+	a, err := New(&MemoryStore{})
+	require.Nil(t, err)
+	for _, e := range events(t, testLog) {
+		a.Add(e)
+	}
+
+	alice, ok := a.Player("alice=")
+	require.True(t, ok)
+	assert.Equal(t, "Alice", alice.Name)
+	assert.Equal(t, 2, alice.Kills)
+	assert.Equal(t, 1, alice.Deaths)
+	assert.Equal(t, 312.25, alice.LongestKill)
+	assert.Equal(t, 2.0, alice.KD())
+
+	bob, _ := a.Player("bob=")
+	assert.Equal(t, 0, bob.Kills)
+	assert.Equal(t, 2, bob.Deaths, "the died line after a kill is not a second death")
+	assert.Equal(t, 40*time.Minute, bob.PlayTime)
+	assert.Equal(t, 0.0, bob.KD())
+
+	_, ok = a.Player("nobody")
+	assert.False(t, ok)
+
+	feed := a.Feed()
+	require.Len(t, feed, 3)
+	assert.Equal(t, Kill{Time: time.Date(2021, 7, 31, 20, 25, 0, 0, time.UTC), Killer: "ZmbM_HermitSkinny_Beige", VictimID: "alice=", Victim: "Alice"}, feed[0])
+	assert.Equal(t, "Alice", feed[2].Killer)
+	assert.Equal(t, "M4-A1", feed[2].Weapon)
+
+	now := time.Date(2021, 7, 31, 21, 0, 10, 0, time.UTC)
+	names := func(board []Stats) []string {
+		var names []string
+		for _, s := range board {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, names(a.Leaderboard(ByKills, 0, now)))
+	assert.Equal(t, []string{"Bob", "Alice"}, names(a.Leaderboard(ByDeaths, 2, now)))
+	assert.Equal(t, []string{"Alice"}, names(a.Leaderboard(ByKD, 1, now)))
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, names(a.Leaderboard(ByLongestKill, 0, now)))
+
+	// Alice and Carol are still online, so their current session counts.
+	board := a.Leaderboard(ByPlayTime, 0, now)
+	assert.Equal(t, []string{"Alice", "Carol", "Bob"}, names(board))
+	assert.Equal(t, time.Hour, board[0].PlayTime)
+
+	a.EndSessions(now)
+	alice, _ = a.Player("alice=")
+	assert.Equal(t, time.Hour, alice.PlayTime)
+}
+
+// TestAggregator_Restart tests that state is persisted and replayed events are not counted twice.
+func TestAggregator_Restart(t *testing.T) {
+	store := FileStore{Path: t.TempDir() + "/leaderboard.json"}
+
+	a, err := New(store)
+	require.Nil(t, err)
+	for _, e := range events(t, testLog) {
+		a.Add(e)
+	}
+	require.Nil(t, a.Save())
+
+	b, err := New(store)
+	require.Nil(t, err)
+	for _, e := range events(t, testLog) {
+		b.Add(e)
+	}
+	alice, _ := b.Player("alice=")
+	assert.Equal(t, 2, alice.Kills)
+	assert.Len(t, b.Feed(), 3)
+
+	b.Add(events(t, "AdminLog started on 2021-07-31 at 21:00:00\n21:00:00 | Player \"Bob\" (DEAD) (id=bob= pos=<1.0, 2.0, 3.0>) killed by Player \"Alice\" (id=alice= pos=<4.0, 5.0, 6.0>) with M4-A1 from 10 meters\n")[0])
+	alice, _ = b.Player("alice=")
+	assert.Equal(t, 3, alice.Kills)
+}
+
+// TestAggregator_RestartAtLastEvent tests that events at the time of the last event are not counted twice after a restart.
+func TestAggregator_RestartAtLastEvent(t *testing.T) {
+	const log = `AdminLog started on 2021-07-31 at 20:00:00
+20:10:00 | Player "Bob" (DEAD) (id=bob= pos=<1.0, 2.0, 3.0>) killed by Player "Alice" (id=alice= pos=<4.0, 5.0, 6.0>) with M4-A1 from 86.5 meters
+`
+	const more = `20:10:00 | Player "Carol" (DEAD) (id=carol= pos=<1.0, 2.0, 3.0>) killed by Player "Alice" (id=alice= pos=<4.0, 5.0, 6.0>) with M4-A1 from 20 meters
+`
+	store := &MemoryStore{}
+
+	a, err := New(store)
+	require.Nil(t, err)
+	for _, e := range events(t, log) {
+		a.Add(e)
+	}
+	require.Nil(t, a.Save())
+
+	// The log is replayed, and a kill in the same second was logged since.
+	b, err := New(store)
+	require.Nil(t, err)
+	for _, e := range events(t, log+more) {
+		b.Add(e)
+	}
+	alice, _ := b.Player("alice=")
+	assert.Equal(t, 2, alice.Kills)
+	assert.Len(t, b.Feed(), 2)
+}
+
+// TestAggregator_ResumeMidFile tests that events are counted when a tailed log is resumed without its header.
+func TestAggregator_ResumeMidFile(t *testing.T) {
+	store := &MemoryStore{}
+	a, err := New(store)
+	require.Nil(t, err)
+	for _, e := range events(t, testLog) {
+		a.Add(e)
+	}
+	require.Nil(t, a.Save())
+
+	// After a restart the tailer resumes from its checkpoint, past the header.
+	const file = "/logs/DayZServer_X1_x64_2021_07_31_200000000.ADM"
+	started := time.Date(2021, 7, 31, 20, 0, 0, 0, time.UTC)
+	lines := make(chan nitrado.LogLine, 2)
+	lines <- nitrado.LogLine{Path: file, Started: started, Text: `20:40:20 | Player "Bob"(id=bob=) has been disconnected`}
+	lines <- nitrado.LogLine{Path: file, Started: started, Text: `21:00:00 | Player "Bob" (DEAD) (id=bob= pos=<1.0, 2.0, 3.0>) killed by Player "Alice" (id=alice= pos=<4.0, 5.0, 6.0>) with M4-A1 from 10 meters`}
+	close(lines)
+	parsed := make(chan admlog.Event, 2)
+	require.Nil(t, new(admlog.Parser).Stream(context.Background(), lines, parsed))
+	close(parsed)
+
+	b, err := New(store)
+	require.Nil(t, err)
+	for e := range parsed {
+		b.Add(e)
+	}
+	alice, _ := b.Player("alice=")
+	assert.Equal(t, 3, alice.Kills)
+	assert.Equal(t, time.Date(2021, 7, 31, 21, 0, 0, 0, time.UTC), b.Feed()[0].Time)
+
+	// Events without a date are counted rather than dropped.
+	b.Add(events(t, `01:00:00 | Player "Carol" (DEAD) (id=carol= pos=<1.0, 2.0, 3.0>) killed by Player "Alice" (id=alice= pos=<4.0, 5.0, 6.0>) with M4-A1 from 10 meters`+"\n")[0])
+	alice, _ = b.Player("alice=")
+	assert.Equal(t, 4, alice.Kills)
+}
+
+// TestAggregator_FeedSize tests that the kill feed is trimmed to FeedSize.
+func TestAggregator_FeedSize(t *testing.T) {
+	a, err := New(&MemoryStore{})
+	require.Nil(t, err)
+	a.FeedSize = 2
+	for _, e := range events(t, testLog) {
+		a.Add(e)
+	}
+	feed := a.Feed()
+	require.Len(t, feed, 2)
+	assert.Equal(t, "Carol", feed[1].Victim)
+}
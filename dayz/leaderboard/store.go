@@ -0,0 +1,93 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store persists the state of an Aggregator.
+type Store interface {
+	// Load returns the saved state, or an empty State if nothing has been saved.
+	Load() (State, error)
+	// Save replaces the saved state.
+	Save(State) error
+}
+
+// MemoryStore keeps the state in memory. It is useful for tests and for
+// aggregators which do not need to survive a restart.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state []byte
+}
+
+// Load returns a copy of the saved state.
+func (m *MemoryStore) Load() (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var state State
+	if m.state == nil {
+		return state, nil
+	}
+	err := json.Unmarshal(m.state, &state)
+	return state, err
+}
+
+// Save stores a copy of state.
+func (m *MemoryStore) Save(state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = data
+	return nil
+}
+
+// FileStore keeps the state in a JSON file.
+type FileStore struct {
+	Path string
+}
+
+// Load reads the state from the file. A missing file returns an empty State.
+func (f FileStore) Load() (State, error) {
+	var state State
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// Save writes the state to a temporary file and renames it over the file, so
+// the file is never left partially written.
+func (f FileStore) Save(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.Path)
+}
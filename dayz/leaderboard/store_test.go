@@ -0,0 +1,46 @@
+package leaderboard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStores tests that each store round trips the state.
+func TestStores(t *testing.T) {
+	state := State{
+		Players:   map[string]*Stats{"alice=": {ID: "alice=", Name: "Alice", Kills: 2, PlayTime: time.Hour}},
+		Online:    map[string]time.Time{"alice=": time.Date(2021, 7, 31, 20, 0, 0, 0, time.UTC)},
+		Feed:      []Kill{{Killer: "Alice", Victim: "Bob"}},
+		LastEvent: time.Date(2021, 7, 31, 21, 0, 0, 0, time.UTC),
+	}
+
+	stores := map[string]Store{
+		"Memory": &MemoryStore{},
+		"File":   FileStore{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			empty, err := store.Load()
+			require.Nil(t, err)
+			assert.Nil(t, empty.Players)
+
+			require.Nil(t, store.Save(state))
+			got, err := store.Load()
+			require.Nil(t, err)
+			assert.Equal(t, state, got)
+		})
+	}
+}
+
+// TestFileStore_Load tests loading a corrupt file.
+func TestFileStore_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.Nil(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err := FileStore{Path: path}.Load()
+	assert.NotNil(t, err)
+}
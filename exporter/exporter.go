@@ -0,0 +1,206 @@
+// Package exporter exports the metrics of Nitrado game servers to Prometheus.
+//
+// The Exporter implements prometheus.Collector. Metrics are fetched from the
+// Nitrado API in the background and scrapes are served from the last
+// refresh, so the API is called at a fixed rate regardless of how often the
+// exporter is scraped.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace       = "nitrado"
+	defaultInterval = time.Minute
+)
+
+var (
+	serviceLabels = []string{"service_id", "name", "game"}
+
+	upDesc = prometheus.NewDesc(namespace+"_up",
+		"Whether the last refresh of the Nitrado API succeeded.", nil, nil)
+	refreshDesc = prometheus.NewDesc(namespace+"_last_refresh_timestamp_seconds",
+		"Time of the last refresh of the Nitrado API.", nil, nil)
+	suspendDesc = prometheus.NewDesc(namespace+"_service_days_until_suspend",
+		"Days until the service is suspended.", serviceLabels, nil)
+	gameServerUpDesc = prometheus.NewDesc(namespace+"_gameserver_up",
+		"Whether the game server is started.", serviceLabels, nil)
+	statusDesc = prometheus.NewDesc(namespace+"_gameserver_status",
+		"The status of the game server, always 1.", append(serviceLabels, "status"), nil)
+	slotsDesc = prometheus.NewDesc(namespace+"_gameserver_slots",
+		"Number of player slots of the game server.", serviceLabels, nil)
+	playersDesc = prometheus.NewDesc(namespace+"_gameserver_players",
+		"Number of players online.", serviceLabels, nil)
+	playersMaxDesc = prometheus.NewDesc(namespace+"_gameserver_players_max",
+		"Maximum number of players reported by the game server query.", serviceLabels, nil)
+	cpuDesc = prometheus.NewDesc(namespace+"_gameserver_cpu_usage_percent",
+		"Latest CPU usage of the game server.", serviceLabels, nil)
+	memoryDesc = prometheus.NewDesc(namespace+"_gameserver_memory_usage_megabytes",
+		"Latest memory usage of the game server.", serviceLabels, nil)
+)
+
+// Exporter collects metrics for every game server of a Nitrado account.
+type Exporter struct {
+	client *nitrado.Client
+
+	Interval time.Duration // How often Run refreshes the metrics, defaults to 1 minute.
+
+	// OnError is called with every error of a refresh. Services which fail
+	// are left out of the metrics until the next successful refresh.
+	OnError func(error)
+
+	mu        sync.RWMutex
+	metrics   []prometheus.Metric
+	up        bool
+	refreshed time.Time
+}
+
+// New returns an Exporter for the services of the account of c.
+func New(c *nitrado.Client) *Exporter {
+	return &Exporter{client: c}
+}
+
+// Describe sends the descriptions of all metrics to ch.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{upDesc, refreshDesc, suspendDesc, gameServerUpDesc, statusDesc, slotsDesc, playersDesc, playersMaxDesc, cpuDesc, memoryDesc} {
+		ch <- d
+	}
+}
+
+// Collect sends the metrics of the last refresh to ch.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	up := 0.0
+	if e.up {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+	if !e.refreshed.IsZero() {
+		ch <- prometheus.MustNewConstMetric(refreshDesc, prometheus.GaugeValue, float64(e.refreshed.Unix()))
+	}
+	for _, m := range e.metrics {
+		ch <- m
+	}
+}
+
+// Run refreshes the metrics immediately and then every Interval until ctx
+// is cancelled.
+func (e *Exporter) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = e.Refresh()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches the metrics of every service from the Nitrado API. It
+// returns the first error, after refreshing every service it can.
+func (e *Exporter) Refresh() error {
+	services, _, err := e.client.Services.List()
+	if err != nil {
+		e.fail(fmt.Errorf("listing services: %w", err))
+		return err
+	}
+
+	var metrics []prometheus.Metric
+	var first error
+	for _, svc := range *services {
+		m, err := e.collectService(svc)
+		if err != nil {
+			err = fmt.Errorf("service %d: %w", svc.ID, err)
+			e.report(err)
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		metrics = append(metrics, m...)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = metrics
+	e.up = first == nil
+	e.refreshed = time.Now()
+
+	return first
+}
+
+// collectService returns the metrics of a single service.
+func (e *Exporter) collectService(svc nitrado.Service) ([]prometheus.Metric, error) {
+	labels := []string{strconv.Itoa(svc.ID), svc.Details.Name, svc.Details.Game}
+	gauge := func(desc *prometheus.Desc, v float64, extra ...string) prometheus.Metric {
+		return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, append(append([]string{}, labels...), extra...)...)
+	}
+
+	metrics := []prometheus.Metric{
+		gauge(suspendDesc, float64(svc.SuspendingIn)/(24*60*60)),
+	}
+	if svc.Type != "gameserver" {
+		return metrics, nil
+	}
+
+	gs, _, err := e.client.GameServers.Get(svc.ID)
+	if err != nil {
+		return nil, err
+	}
+	up := 0.0
+	if gs.Status == "started" {
+		up = 1
+	}
+	metrics = append(metrics,
+		gauge(gameServerUpDesc, up),
+		gauge(statusDesc, 1, gs.Status),
+		gauge(slotsDesc, float64(gs.Slots)),
+		gauge(playersDesc, float64(gs.Query.PlayerCurrent)),
+		gauge(playersMaxDesc, float64(gs.Query.PlayerMax)),
+	)
+
+	stats, _, err := e.client.GameServerStats.Get(svc.ID, nitrado.GSStatsOptions{Hours: 1})
+	if err != nil {
+		return nil, err
+	}
+	if n := len(stats.CPUUsage); n > 0 {
+		metrics = append(metrics, gauge(cpuDesc, stats.CPUUsage[n-1].Value))
+	}
+	if n := len(stats.MemoryUsage); n > 0 {
+		metrics = append(metrics, gauge(memoryDesc, stats.MemoryUsage[n-1].Value))
+	}
+
+	return metrics, nil
+}
+
+// fail records a refresh which returned no metrics at all.
+func (e *Exporter) fail(err error) {
+	e.report(err)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.up = false
+	e.refreshed = time.Now()
+}
+
+func (e *Exporter) report(err error) {
+	if e.OnError != nil {
+		e.OnError(err)
+	}
+}
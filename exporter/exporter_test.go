@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/danstis/go-nitrado/nitradotest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a fake Nitrado API with a game server, with three
+// players online, and a web space service.
+func newServer(t *testing.T) *nitradotest.Server {
+	server := nitradotest.NewServer()
+	t.Cleanup(server.Close)
+	server.Now = func() time.Time { return time.Unix(1622360000, 0) }
+
+	web := nitrado.Service{ID: 1234567, Type: "webspace", SuspendingIn: 43200}
+	web.Details.Name = "Web"
+	server.AddService(web)
+	svc := nitrado.Service{ID: 7654321, SuspendingIn: 172800}
+	svc.Details.Name = "My Server"
+	svc.Details.Game = "DayZ (PC)"
+	gs := nitrado.GameServer{Slots: 10}
+	gs.Query.PlayerMax = 10
+	server.AddGameServer(svc, gs)
+	server.SetPlayers(7654321,
+		nitrado.Player{Name: "a", Online: true},
+		nitrado.Player{Name: "b", Online: true},
+		nitrado.Player{Name: "c", Online: true},
+		nitrado.Player{Name: "d"},
+	)
+	var stats nitrado.GSStats
+	require.Nil(t, json.Unmarshal([]byte(`{"cpuUsage":[[12.5,1622359920],[25,1622359980]],"memoryUsage":[[4732,1622359920],[4734.5,1622359980]]}`), &stats))
+	server.SetStats(7654321, stats)
+	return server
+}
+
+// TestExporter_Collect tests the Exporter Refresh() and Collect() methods.
+func TestExporter_Collect(t *testing.T) {
+	server := newServer(t)
+	e := New(server.Client())
+
+	require.Nil(t, e.Refresh())
+	assert.Equal(t, []string{
+		"GET /services",
+		"GET /services/7654321/gameservers",
+		"GET /services/7654321/gameservers/stats",
+	}, server.Requests())
+
+	want := `
+# HELP nitrado_gameserver_cpu_usage_percent Latest CPU usage of the game server.
+# TYPE nitrado_gameserver_cpu_usage_percent gauge
+nitrado_gameserver_cpu_usage_percent{game="DayZ (PC)",name="My Server",service_id="7654321"} 25
+# HELP nitrado_gameserver_memory_usage_megabytes Latest memory usage of the game server.
+# TYPE nitrado_gameserver_memory_usage_megabytes gauge
+nitrado_gameserver_memory_usage_megabytes{game="DayZ (PC)",name="My Server",service_id="7654321"} 4734.5
+# HELP nitrado_gameserver_players Number of players online.
+# TYPE nitrado_gameserver_players gauge
+nitrado_gameserver_players{game="DayZ (PC)",name="My Server",service_id="7654321"} 3
+# HELP nitrado_gameserver_players_max Maximum number of players reported by the game server query.
+# TYPE nitrado_gameserver_players_max gauge
+nitrado_gameserver_players_max{game="DayZ (PC)",name="My Server",service_id="7654321"} 10
+# HELP nitrado_gameserver_slots Number of player slots of the game server.
+# TYPE nitrado_gameserver_slots gauge
+nitrado_gameserver_slots{game="DayZ (PC)",name="My Server",service_id="7654321"} 10
+# HELP nitrado_gameserver_status The status of the game server, always 1.
+# TYPE nitrado_gameserver_status gauge
+nitrado_gameserver_status{game="DayZ (PC)",name="My Server",service_id="7654321",status="started"} 1
+# HELP nitrado_gameserver_up Whether the game server is started.
+# TYPE nitrado_gameserver_up gauge
+nitrado_gameserver_up{game="DayZ (PC)",name="My Server",service_id="7654321"} 1
+# HELP nitrado_service_days_until_suspend Days until the service is suspended.
+# TYPE nitrado_service_days_until_suspend gauge
+nitrado_service_days_until_suspend{game="",name="Web",service_id="1234567"} 0.5
+nitrado_service_days_until_suspend{game="DayZ (PC)",name="My Server",service_id="7654321"} 2
+# HELP nitrado_up Whether the last refresh of the Nitrado API succeeded.
+# TYPE nitrado_up gauge
+nitrado_up 1
+`
+	names := []string{
+		"nitrado_gameserver_cpu_usage_percent", "nitrado_gameserver_memory_usage_megabytes",
+		"nitrado_gameserver_players", "nitrado_gameserver_players_max", "nitrado_gameserver_slots",
+		"nitrado_gameserver_status", "nitrado_gameserver_up", "nitrado_service_days_until_suspend", "nitrado_up",
+	}
+	assert.Nil(t, testutil.CollectAndCompare(e, strings.NewReader(want), names...))
+	assert.Equal(t, 1, testutil.CollectAndCount(e, "nitrado_last_refresh_timestamp_seconds"))
+
+	// Scrapes are served from the cache.
+	testutil.CollectAndCount(e)
+	assert.Len(t, server.Requests(), 3)
+}
+
+// TestExporter_RefreshError tests that the Exporter keeps the services which refresh and reports the others.
+func TestExporter_RefreshError(t *testing.T) {
+	server := newServer(t)
+	e := New(server.Client())
+	var errs []error
+	e.OnError = func(err error) { errs = append(errs, err) }
+	require.Nil(t, e.Refresh())
+
+	server.Inject(nitradotest.Fault{Path: "/services/*/gameservers/stats", Status: http.StatusServiceUnavailable})
+	assert.Error(t, e.Refresh())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "service 7654321")
+	assert.Nil(t, testutil.CollectAndCompare(e, strings.NewReader(`
+# HELP nitrado_up Whether the last refresh of the Nitrado API succeeded.
+# TYPE nitrado_up gauge
+nitrado_up 0
+`), "nitrado_up"))
+	assert.Equal(t, 1, testutil.CollectAndCount(e, "nitrado_service_days_until_suspend"))
+	assert.Equal(t, 0, testutil.CollectAndCount(e, "nitrado_gameserver_up"))
+
+	server.ClearFaults()
+	require.Nil(t, e.Refresh())
+	assert.Equal(t, 1, testutil.CollectAndCount(e, "nitrado_gameserver_up"))
+}
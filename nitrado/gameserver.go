@@ -54,46 +54,9 @@ type GameServer struct {
 			Database string `json:"database,omitempty"`
 		} `json:"mysql,omitempty"`
 	} `json:"credentials,omitempty"`
-	Settings struct {
-		Config struct {
-			Hostname                    string `json:"hostname,omitempty"`
-			VonCodecQuality             string `json:"vonCodecQuality,omitempty"`
-			DisableVoN                  string `json:"disableVoN,omitempty"`
-			Password                    string `json:"password,omitempty"`
-			ServerTimeAcceleration      string `json:"serverTimeAcceleration,omitempty"`
-			ServerNightTimeAcceleration string `json:"serverNightTimeAcceleration,omitempty"`
-			ServerTimePersistent        string `json:"serverTimePersistent,omitempty"`
-			Disable3RdPerson            string `json:"disable3rdPerson,omitempty"`
-			DisableCrosshair            string `json:"disableCrosshair,omitempty"`
-			UseServerTime               string `json:"useServerTime,omitempty"`
-			CustomServerTime            string `json:"customServerTime,omitempty"`
-			EnableMouseAndKeyboard      string `json:"enableMouseAndKeyboard,omitempty"`
-			EnableWhitelist             string `json:"enableWhitelist,omitempty"`
-			Mission                     string `json:"mission,omitempty"`
-			AdminLogPlayerHitsOnly      string `json:"adminLogPlayerHitsOnly,omitempty"`
-			AdminLogPlacement           string `json:"adminLogPlacement,omitempty"`
-			AdminLogBuildActions        string `json:"adminLogBuildActions,omitempty"`
-			AdminLogPlayerList          string `json:"adminLogPlayerList,omitempty"`
-			LightingConfig              string `json:"lightingConfig,omitempty"`
-			DisablePersonalLight        string `json:"disablePersonalLight,omitempty"`
-			DisableBaseDamage           string `json:"disableBaseDamage,omitempty"`
-			DisableContainerDamage      string `json:"disableContainerDamage,omitempty"`
-			EnableCFGGameplayFile       string `json:"enableCfgGameplayFile,omitempty"`
-		} `json:"config,omitempty"`
-		General struct {
-			ExpertMode     string `json:"expertMode,omitempty"`
-			AdminPassword  string `json:"admin-password,omitempty"`
-			Nolog          string `json:"nolog,omitempty"`
-			RconPassword   string `json:"rcon-password,omitempty"`
-			AdditionalMods string `json:"additionalMods,omitempty"`
-			Bans           string `json:"bans,omitempty"`
-			Whitelist      string `json:"whitelist,omitempty"`
-			Resetmission   string `json:"resetmission,omitempty"`
-			Priority       string `json:"priority,omitempty"`
-		} `json:"general,omitempty"`
-	} `json:"settings,omitempty"`
-	Quota interface{} `json:"quota,omitempty"`
-	Query struct {
+	Settings Settings    `json:"settings,omitempty"` // See Settings.DayZ, Settings.Minecraft and Settings.ARK for typed views.
+	Quota    interface{} `json:"quota,omitempty"`
+	Query    struct {
 		ServerName    string `json:"server_name,omitempty"`
 		ConnectIP     string `json:"connect_ip,omitempty"`
 		Map           string `json:"map,omitempty"`
@@ -105,8 +68,9 @@ type GameServer struct {
 
 // GameServerDetailResp contains the query response from the Nitrado API for the GameServer operation
 type GameServerDetailResp struct {
-	Status string `json:"status,omitempty"`
-	Data   struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		GameServer GameServer `json:"gameserver,omitempty"`
 	} `json:"data,omitempty"`
 }
@@ -132,6 +96,9 @@ func (s *GameServersService) Get(serviceID int) (*GameServer, *http.Response, er
 	if err != nil {
 		return nil, resp, err
 	}
+	if gameServerDetailResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", gameServerDetailResp.Status, gameServerDetailResp.Message)
+	}
 
 	return &gameServerDetailResp.Data.GameServer, resp, nil
 }
@@ -0,0 +1,145 @@
+package nitrado
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AccessListService provides access to the ban list and whitelist of a
+// GameServer, which are stored as newline separated general settings.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Details
+type AccessListService apiService
+
+// ErrAccessListChanged is returned by Save when the list was changed on the
+// server after it was read.
+var ErrAccessListChanged = errors.New("access list changed since it was read")
+
+// AccessList is a set of player names or IDs from a GameServer setting. The
+// order of the entries is kept when the list is saved.
+type AccessList struct {
+	ServiceID int
+	Key       string // The general setting holding the list, "bans" or "whitelist".
+
+	original string
+	entries  []string
+}
+
+// newAccessList parses a newline separated list, ignoring blank lines and
+// duplicates.
+func newAccessList(serviceID int, key, value string) *AccessList {
+	l := &AccessList{ServiceID: serviceID, Key: key, original: value}
+	for _, line := range strings.Split(value, "\n") {
+		l.Add(line)
+	}
+	return l
+}
+
+// Entries returns the entries of the list.
+func (l *AccessList) Entries() []string {
+	return append([]string(nil), l.entries...)
+}
+
+// Len returns the number of entries in the list.
+func (l *AccessList) Len() int {
+	return len(l.entries)
+}
+
+// Contains reports whether entry is in the list.
+func (l *AccessList) Contains(entry string) bool {
+	return l.index(entry) >= 0
+}
+
+// Add adds entry to the end of the list. It reports false when the entry is
+// blank or already in the list.
+func (l *AccessList) Add(entry string) bool {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || l.Contains(entry) {
+		return false
+	}
+	l.entries = append(l.entries, entry)
+	return true
+}
+
+// Remove removes entry from the list. It reports false when the entry was not
+// in the list.
+func (l *AccessList) Remove(entry string) bool {
+	i := l.index(entry)
+	if i < 0 {
+		return false
+	}
+	l.entries = append(l.entries[:i], l.entries[i+1:]...)
+	return true
+}
+
+// String returns the list in the format stored by Nitrado.
+func (l *AccessList) String() string {
+	return strings.Join(l.entries, "\r\n")
+}
+
+func (l *AccessList) index(entry string) int {
+	entry = strings.TrimSpace(entry)
+	for i, e := range l.entries {
+		if e == entry {
+			return i
+		}
+	}
+	return -1
+}
+
+// Bans returns the ban list of a GameServer by service ID.
+func (s *AccessListService) Bans(serviceID int) (*AccessList, *http.Response, error) {
+	return s.get(serviceID, "bans")
+}
+
+// Whitelist returns the whitelist of a GameServer by service ID.
+func (s *AccessListService) Whitelist(serviceID int) (*AccessList, *http.Response, error) {
+	return s.get(serviceID, "whitelist")
+}
+
+// Save writes the list back to the GameServer in a single settings update.
+// ErrAccessListChanged is returned without writing anything when the list on
+// the server no longer matches the one that was read. Read the list again and
+// reapply the changes to resolve the conflict.
+func (s *AccessListService) Save(l *AccessList) error {
+	value := l.String()
+	sameList := func(a, b string) bool {
+		return sameEntries(newAccessList(0, l.Key, a).entries, newAccessList(0, l.Key, b).entries)
+	}
+	err := s.client.GameServersSettings.updateIfUnchanged(l.ServiceID, l.Key, l.original, value, sameList)
+	if errors.Is(err, errSettingChanged) {
+		return fmt.Errorf("%w: %s", ErrAccessListChanged, l.Key)
+	}
+	if err != nil {
+		return err
+	}
+	l.original = value
+
+	return nil
+}
+
+// get reads a list from the general settings of a GameServer.
+func (s *AccessListService) get(serviceID int, key string) (*AccessList, *http.Response, error) {
+	gs, resp, err := s.client.GameServers.Get(serviceID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	value, _ := gs.Settings.Value("general", key)
+	return newAccessList(serviceID, key, value), resp, nil
+}
+
+// sameEntries reports whether a and b hold the same entries in the same order.
+func sameEntries(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package nitrado
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accessListServer serves the general settings of a game server and records updates.
+type accessListServer struct {
+	mu       sync.Mutex
+	settings map[string]string
+	updates  int
+}
+
+func (s *accessListServer) register(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/services/7654321/gameservers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var resp GameServerDetailResp
+		resp.Status = "success"
+		resp.Data.GameServer.Settings = Settings{"general": s.settings}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/settings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		assert.Equal(t, "general", r.URL.Query().Get("category"))
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.settings[r.URL.Query().Get("key")] = r.URL.Query().Get("value")
+		s.updates++
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	})
+}
+
+// TestAccessList tests the AccessList set operations.
+func TestAccessList(t *testing.T) {
+	l := newAccessList(1, "bans", "Gamer Tag 1\r\n\r\n Gamer Tag 2 \nGamer Tag 1\n")
+	assert.Equal(t, []string{"Gamer Tag 1", "Gamer Tag 2"}, l.Entries())
+	assert.Equal(t, 2, l.Len())
+	assert.True(t, l.Contains("Gamer Tag 2"))
+	assert.False(t, l.Contains("gamer tag 2"))
+
+	assert.True(t, l.Add("Gamer Tag 3"))
+	assert.False(t, l.Add("Gamer Tag 3"))
+	assert.False(t, l.Add("  "))
+	assert.True(t, l.Remove("Gamer Tag 1"))
+	assert.False(t, l.Remove("Gamer Tag 1"))
+	assert.Equal(t, "Gamer Tag 2\r\nGamer Tag 3", l.String())
+}
+
+// TestAccessListService_Save tests the AccessListService Bans(), Whitelist() and Save() methods.
+func TestAccessListService_Save(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &accessListServer{settings: map[string]string{
+		"bans":      "Gamer Tag 1\r\nGamer Tag 2",
+		"whitelist": "",
+	}}
+	server.register(t, mux)
+
+	bans, _, err := client.AccessLists.Bans(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Gamer Tag 1", "Gamer Tag 2"}, bans.Entries())
+	bans.Remove("Gamer Tag 1")
+	bans.Add("Gamer Tag 3")
+	require.Nil(t, client.AccessLists.Save(bans))
+	assert.Equal(t, "Gamer Tag 2\r\nGamer Tag 3", server.settings["bans"])
+
+	// Saving again after a successful save does not report a conflict.
+	bans.Add("Gamer Tag 4")
+	require.Nil(t, client.AccessLists.Save(bans))
+	assert.Equal(t, 2, server.updates)
+
+	whitelist, _, err := client.AccessLists.Whitelist(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, 0, whitelist.Len())
+	whitelist.Add("Gamer Tag 1")
+
+	// A change made elsewhere after the list was read is detected.
+	server.mu.Lock()
+	server.settings["whitelist"] = "Gamer Tag 5"
+	server.mu.Unlock()
+	err = client.AccessLists.Save(whitelist)
+	assert.ErrorIs(t, err, ErrAccessListChanged)
+	assert.Equal(t, "Gamer Tag 5", server.settings["whitelist"])
+	assert.Equal(t, 2, server.updates)
+}
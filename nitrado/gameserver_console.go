@@ -0,0 +1,64 @@
+package nitrado
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ConsoleService provides access to the console of a GameServer in the Nitrado API.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-SendCommand
+type ConsoleService apiService
+
+// ConsoleResult is the result of a console command.
+type ConsoleResult struct {
+	Message string `json:"message,omitempty"` // The status message from Nitrado, e.g. "Command has been sent".
+	Output  string `json:"output,omitempty"`  // The response of the game server, if the game returns one.
+}
+
+// ConsoleResp contains the response from the Nitrado API for a console command.
+type ConsoleResp struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
+		Output string `json:"output,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// ConsoleSendOptions contains the command sent to the app server.
+type ConsoleSendOptions struct {
+	Command string `url:"command"`
+}
+
+// Send runs a command on the console of a GameServer by service ID, using the
+// Nitrado app server. Commands are game specific, for example "say Hello" on
+// Minecraft.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-SendCommand
+func (s *ConsoleService) Send(serviceID int, command string) (*ConsoleResult, *http.Response, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, nil, fmt.Errorf("command must not be blank")
+	}
+	u := fmt.Sprintf("services/%v/gameservers/app_server/command", serviceID)
+	u, err := addOptions(u, ConsoleSendOptions{Command: command})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var consoleResp *ConsoleResp
+	resp, err := s.client.Do(req, &consoleResp)
+	if err != nil {
+		return nil, resp, err
+	}
+	if consoleResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", consoleResp.Status, consoleResp.Message)
+	}
+
+	return &ConsoleResult{Message: consoleResp.Message, Output: consoleResp.Data.Output}, resp, nil
+}
@@ -0,0 +1,72 @@
+package nitrado
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConsoleService_Send tests the ConsoleService Send() method.
+func TestConsoleService_Send(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/services/7654321/gameservers/app_server/command", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		switch r.URL.Query().Get("command") {
+		case "list":
+			_, _ = fmt.Fprint(w, `{"status":"success","message":"Command has been sent","data":{"output":"There are 0 of a max of 20 players online"}}`)
+		default:
+			_, _ = fmt.Fprint(w, `{"status":"success","message":"Command has been sent"}`)
+		}
+	})
+	mux.HandleFunc("/services/999/gameservers/app_server/command", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"The game server is not running."}`)
+	})
+
+	tests := []struct {
+		name      string
+		serviceID int
+		command   string
+		want      *ConsoleResult
+		wantErr   bool
+	}{
+		{
+			name:      "Command with output",
+			serviceID: 7654321,
+			command:   "list",
+			want:      &ConsoleResult{Message: "Command has been sent", Output: "There are 0 of a max of 20 players online"},
+		},
+		{
+			name:      "Command without output",
+			serviceID: 7654321,
+			command:   "say Hello world",
+			want:      &ConsoleResult{Message: "Command has been sent"},
+		},
+		{
+			name:      "Server error",
+			serviceID: 999,
+			command:   "list",
+			wantErr:   true,
+		},
+		{
+			name:      "Blank command",
+			serviceID: 7654321,
+			command:   " ",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := client.Console.Send(tt.serviceID, tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConsoleService.Send() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,8 +2,13 @@ package nitrado
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"path"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Generated structs from https://mholt.github.io/json-to-go/
@@ -29,16 +34,18 @@ type File struct {
 
 // FileListResp contains a listing of the files at a location
 type FileListResp struct {
-	Status string `json:"status,omitempty"`
-	Data   struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Entries []File `json:"entries,omitempty"`
 	} `json:"data,omitempty"`
 }
 
 // FileDownloadResp contains the response object from the download method on a fileserver
 type FileDownloadResp struct {
-	Status string `json:"status,omitempty"`
-	Data   struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Token struct {
 			URL   string `json:"url,omitempty"`
 			Token string `json:"token,omitempty"`
@@ -46,10 +53,55 @@ type FileDownloadResp struct {
 	} `json:"data,omitempty"`
 }
 
+// FileServerResp contains the response object from file operations that only return a status
+type FileServerResp struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// File types returned by the file server.
+const (
+	FileTypeFile = "file"
+	FileTypeDir  = "dir"
+)
+
+// FileSortKey selects the field used to sort the results of a list request.
+type FileSortKey string
+
+// The keys that file listings can be sorted by.
+const (
+	FileSortModified FileSortKey = "modified"
+	FileSortName     FileSortKey = "name"
+	FileSortSize     FileSortKey = "size"
+	FileSortType     FileSortKey = "type"
+	FileSortNone     FileSortKey = "none" // Keep the order returned by the server, or order recursive listings by path.
+)
+
+// defaultListConcurrency is used when FileServerListOptions.Concurrency is not set.
+const defaultListConcurrency = 4
+
 // FileServerListOptions controls the query string settings that a list request can take.
+//
+// Only Dir and Search are sent to the Nitrado API, the remaining options are
+// applied to the results on the client.
 type FileServerListOptions struct {
-	Dir    string `url:"dir,omitempty"`
+	Dir string `url:"dir,omitempty"`
+
+	// Search only returns entries whose name matches this pattern, such as
+	// "*.ADM". When Recursive, it is matched on the client as a path.Match
+	// pattern, as a search on the server would also hide the subdirectories.
 	Search string `url:"search,omitempty"`
+
+	SortBy   FileSortKey `url:"-"` // Defaults to FileSortModified.
+	SortDesc bool        `url:"-"`
+
+	Type           string    `url:"-"` // Only return entries of this type, FileTypeFile or FileTypeDir.
+	Names          []string  `url:"-"` // Only return entries whose name matches one of these path.Match patterns.
+	ModifiedAfter  time.Time `url:"-"` // Only return entries modified at or after this time.
+	ModifiedBefore time.Time `url:"-"` // Only return entries modified before this time.
+
+	Recursive   bool `url:"-"` // Also list the contents of all subdirectories.
+	Concurrency int  `url:"-"` // Maximum number of concurrent list requests when Recursive, defaults to 4.
 }
 
 // FileServerDownloadOptions controls the query string settings that a download request can take.
@@ -63,10 +115,45 @@ type FileServerUploadOptions struct {
 	File string `url:"file,omitempty"`
 }
 
+// FileServerDeleteOptions controls the query string settings that a delete request can take.
+type FileServerDeleteOptions struct {
+	Path string `url:"path,omitempty"`
+}
+
+// FileServerMkdirOptions controls the query string settings that a mkdir request can take.
+type FileServerMkdirOptions struct {
+	Path string `url:"path,omitempty"`
+	Name string `url:"name,omitempty"`
+}
+
 // List files on a GameServer.
 //
 // Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-GameserverFilesList
 func (s *FileServerService) List(svc Service, opts FileServerListOptions) ([]File, *http.Response, error) {
+	listOpts := opts
+	if opts.Recursive {
+		listOpts.Search = ""
+	}
+	files, resp, err := s.list(svc, listOpts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if opts.Recursive {
+		files, err = s.listSubdirs(svc, listOpts, files)
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	files = opts.filter(files)
+	opts.sort(files)
+
+	return files, resp, nil
+}
+
+// list returns the unfiltered contents of a single directory.
+func (s *FileServerService) list(svc Service, opts FileServerListOptions) ([]File, *http.Response, error) {
 	u := fmt.Sprintf("services/%v/gameservers/file_server/list", svc.ID)
 	u, err := addOptions(u, opts)
 	if err != nil {
@@ -83,13 +170,198 @@ func (s *FileServerService) List(svc Service, opts FileServerListOptions) ([]Fil
 	if err != nil {
 		return nil, resp, err
 	}
+	if fileListResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", fileListResp.Status, fileListResp.Message)
+	}
+
+	return fileListResp.Data.Entries, resp, nil
+}
+
+// listSubdirs walks every directory in files, appending their contents,
+// while running at most opts.Concurrency list requests at once.
+func (s *FileServerService) listSubdirs(svc Service, opts FileServerListOptions, files []File) ([]File, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultListConcurrency
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	var walk func(dir string)
+	walk = func(dir string) {
+		defer wg.Done()
+		sem <- struct{}{}
+		dirOpts := opts
+		dirOpts.Dir = dir
+		entries, _, err := s.list(svc, dirOpts)
+		<-sem
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("listing %q: %w", dir, err)
+			}
+			return
+		}
+		files = append(files, entries...)
+		for _, e := range entries {
+			if e.Type == FileTypeDir {
+				wg.Add(1)
+				go walk(e.Path)
+			}
+		}
+	}
+
+	mu.Lock()
+	for _, f := range files {
+		if f.Type == FileTypeDir {
+			wg.Add(1)
+			go walk(f.Path)
+		}
+	}
+	mu.Unlock()
+	wg.Wait()
+
+	return files, firstErr
+}
+
+// filter returns the files matching the client side filters in opts.
+func (opts FileServerListOptions) filter(files []File) []File {
+	filtered := files[:0]
+	for _, f := range files {
+		if opts.Type != "" && f.Type != opts.Type {
+			continue
+		}
+		if opts.Recursive && opts.Search != "" && !matchName([]string{opts.Search}, f.Name) {
+			continue
+		}
+		if len(opts.Names) > 0 && !matchName(opts.Names, f.Name) {
+			continue
+		}
+		modified := time.Unix(int64(f.ModifiedAt), 0)
+		if !opts.ModifiedAfter.IsZero() && modified.Before(opts.ModifiedAfter) {
+			continue
+		}
+		if !opts.ModifiedBefore.IsZero() && !modified.Before(opts.ModifiedBefore) {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered
+}
+
+// sort orders files in place by the sort key in opts, keeping the server
+// order for equal entries.
+func (opts FileServerListOptions) sort(files []File) {
+	var less func(a, b File) bool
+	switch opts.SortBy {
+	case FileSortNone:
+		if !opts.Recursive {
+			return
+		}
+		// The subdirectories are listed concurrently, so there is no
+		// server order to keep.
+		less = func(a, b File) bool { return a.Path < b.Path }
+	case FileSortName:
+		less = func(a, b File) bool { return a.Name < b.Name }
+	case FileSortSize:
+		less = func(a, b File) bool { return a.Size < b.Size }
+	case FileSortType:
+		less = func(a, b File) bool { return a.Type < b.Type }
+	default:
+		less = func(a, b File) bool { return a.ModifiedAt < b.ModifiedAt }
+	}
 
-	// Sort the files by Modified date
-	sort.Slice(fileListResp.Data.Entries, func(i, j int) bool {
-		return fileListResp.Data.Entries[i].ModifiedAt < fileListResp.Data.Entries[j].ModifiedAt
+	sort.SliceStable(files, func(i, j int) bool {
+		if opts.SortDesc {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
 	})
+}
 
-	return fileListResp.Data.Entries, resp, nil
+// matchName reports whether name matches any of the path.Match patterns.
+func matchName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Bookmarks lists the well-known directories for the game installed on a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-GameserverFilesBookmarks
+func (s *FileServerService) Bookmarks(svc Service) ([]string, *http.Response, error) {
+	u := fmt.Sprintf("services/%v/gameservers/file_server/bookmarks", svc.ID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fileBookmarks *FileBookmarks
+	resp, err := s.client.Do(req, &fileBookmarks)
+	if err != nil {
+		return nil, resp, err
+	}
+	if fileBookmarks.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", fileBookmarks.Status, fileBookmarks.Message)
+	}
+
+	return fileBookmarks.Data.Bookmarks, resp, nil
+}
+
+// ResolveBookmark returns the bookmarked directory on a GameServer matching
+// name, such as "config" or "mpmissions". The result can be used as the Dir
+// of FileServerListOptions.
+func (s *FileServerService) ResolveBookmark(svc Service, name string) (string, *http.Response, error) {
+	bookmarks, resp, err := s.Bookmarks(svc)
+	if err != nil {
+		return "", resp, err
+	}
+
+	dir, err := resolveBookmark(bookmarks, name)
+	return dir, resp, err
+}
+
+// resolveBookmark finds the bookmark for name. A bookmark whose last path
+// element matches name is preferred, otherwise the shortest bookmark with a
+// matching path element is used. Matching ignores case and a trailing "s", so
+// "logs" will match a "log" directory.
+func resolveBookmark(bookmarks []string, name string) (string, error) {
+	want := normaliseBookmark(name)
+	if want == "" {
+		return "", fmt.Errorf("bookmark name must not be blank")
+	}
+
+	var best string
+	for _, b := range bookmarks {
+		elems := strings.Split(strings.Trim(b, "/"), "/")
+		if normaliseBookmark(elems[len(elems)-1]) == want {
+			return b, nil
+		}
+		for _, e := range elems {
+			if normaliseBookmark(e) == want && (best == "" || len(b) < len(best)) {
+				best = b
+			}
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no bookmark matches %q", name)
+	}
+
+	return best, nil
+}
+
+func normaliseBookmark(s string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.Trim(s, "/")), "s")
 }
 
 // Download a given file on a GameServer.
@@ -112,10 +384,64 @@ func (s *FileServerService) Download(svc Service, opts FileServerDownloadOptions
 	if err != nil {
 		return "", resp, err
 	}
+	if fileDownloadResp.Status != "success" {
+		return "", resp, fmt.Errorf("status %q (%q)", fileDownloadResp.Status, fileDownloadResp.Message)
+	}
 
 	return fileDownloadResp.Data.Token.URL, resp, nil
 }
 
+// fetch downloads the contents of a file on a GameServer. The caller is
+// responsible for closing the returned body.
+func (s *FileServerService) fetch(svc Service, file string) (io.ReadCloser, *http.Response, error) {
+	return s.fetchFrom(svc, file, 0)
+}
+
+// fetchFrom downloads the contents of a file on a GameServer starting at
+// offset, using a range request. If the server ignores the range, the
+// leading bytes are discarded from the returned body.
+func (s *FileServerService) fetchFrom(svc Service, file string, offset int64) (io.ReadCloser, *http.Response, error) {
+	link, resp, err := s.Download(svc, FileServerDownloadOptions{File: file})
+	if err != nil {
+		return nil, resp, err
+	}
+	if link == "" {
+		return nil, resp, fmt.Errorf("no download link returned for %q", file)
+	}
+
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	if s.client.UserAgent != "" {
+		req.Header.Set("User-Agent", s.client.UserAgent)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err = s.client.client.Do(req)
+	if err != nil {
+		return nil, resp, err
+	}
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		resp.Body.Close()
+		return io.NopCloser(strings.NewReader("")), resp, nil
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, resp, fmt.Errorf("download of %q failed with status %q", file, resp.Status)
+	}
+	if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil && err != io.EOF {
+			resp.Body.Close()
+			return nil, resp, err
+		}
+	}
+
+	return resp.Body, resp, nil
+}
+
 // Upload a given file on a GameServer.
 //
 // Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-GameserverFilesUpload
@@ -136,6 +462,102 @@ func (s *FileServerService) Upload(svc Service, opts FileServerUploadOptions) (F
 	if err != nil {
 		return FileDownloadResp{}, resp, err
 	}
+	if fileDownloadResp.Status != "success" {
+		return FileDownloadResp{}, resp, fmt.Errorf("status %q (%q)", fileDownloadResp.Status, fileDownloadResp.Message)
+	}
 
 	return *fileDownloadResp, resp, nil
 }
+
+// Delete a file or directory on a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-GameserverFilesDelete
+func (s *FileServerService) Delete(svc Service, opts FileServerDeleteOptions) (*http.Response, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("path must not be blank")
+	}
+	u := fmt.Sprintf("services/%v/gameservers/file_server/delete", svc.ID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileServerResp *FileServerResp
+	resp, err := s.client.Do(req, &fileServerResp)
+	if err != nil {
+		return resp, err
+	}
+	if fileServerResp.Status != "success" {
+		return resp, fmt.Errorf("status %q (%q)", fileServerResp.Status, fileServerResp.Message)
+	}
+
+	return resp, nil
+}
+
+// Mkdir creates a new directory named opts.Name inside opts.Path on a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-GameserverFilesMkdir
+func (s *FileServerService) Mkdir(svc Service, opts FileServerMkdirOptions) (*http.Response, error) {
+	if opts.Path == "" || opts.Name == "" {
+		return nil, fmt.Errorf("path and name must not be blank. path=%q, name=%q", opts.Path, opts.Name)
+	}
+	u := fmt.Sprintf("services/%v/gameservers/file_server/mkdir", svc.ID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileServerResp *FileServerResp
+	resp, err := s.client.Do(req, &fileServerResp)
+	if err != nil {
+		return resp, err
+	}
+	if fileServerResp.Status != "success" {
+		return resp, fmt.Errorf("status %q (%q)", fileServerResp.Status, fileServerResp.Message)
+	}
+
+	return resp, nil
+}
+
+// put uploads the contents of body as the file name in the directory dir on a
+// GameServer, using the token returned by Upload.
+func (s *FileServerService) put(svc Service, dir, name string, body io.Reader) (*http.Response, error) {
+	upload, resp, err := s.Upload(svc, FileServerUploadOptions{Path: dir, File: name})
+	if err != nil {
+		return resp, err
+	}
+	if upload.Data.Token.URL == "" {
+		return resp, fmt.Errorf("no upload link returned for %q", path.Join(dir, name))
+	}
+
+	req, err := http.NewRequest("POST", upload.Data.Token.URL, body)
+	if err != nil {
+		return resp, err
+	}
+	req.Header.Set("Content-Type", "application/binary")
+	req.Header.Set("token", upload.Data.Token.Token)
+	if s.client.UserAgent != "" {
+		req.Header.Set("User-Agent", s.client.UserAgent)
+	}
+
+	resp, err = s.client.client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return resp, fmt.Errorf("upload of %q failed with status %q", path.Join(dir, name), resp.Status)
+	}
+
+	return resp, nil
+}
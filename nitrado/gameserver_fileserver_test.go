@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // JSON minified using https://codebeautify.org/jsonminifier
@@ -278,3 +279,230 @@ func TestFileServerService_Upload(t *testing.T) {
 		})
 	}
 }
+
+// TestFileServerService_Delete tests the FileServerService Delete() method.
+func TestFileServerService_Delete(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/services/7654321/gameservers/file_server/delete", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		_, _ = fmt.Fprint(w, `{"status":"success","message":"File has been deleted."}`)
+	})
+	mux.HandleFunc("/services/999/gameservers/file_server/delete", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"File not found."}`)
+	})
+
+	tests := []struct {
+		name    string
+		svc     Service
+		opts    FileServerDeleteOptions
+		wantErr bool
+	}{
+		{name: "Delete a file", svc: Service{ID: 7654321}, opts: FileServerDeleteOptions{Path: "/games/ni1_1/a.txt"}},
+		{name: "Delete failure", svc: Service{ID: 999}, opts: FileServerDeleteOptions{Path: "/games/ni1_1/a.txt"}, wantErr: true},
+		{name: "Missing path", svc: Service{ID: 7654321}, opts: FileServerDeleteOptions{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.FileServerService.Delete(tt.svc, tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("FileServerService.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestFileServerService_Mkdir tests the FileServerService Mkdir() method.
+func TestFileServerService_Mkdir(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/services/7654321/gameservers/file_server/mkdir", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if r.URL.Query().Get("path") != "/games/ni1_1" || r.URL.Query().Get("name") != "mods" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		_, _ = fmt.Fprint(w, `{"status":"success","message":"Directory has been created."}`)
+	})
+
+	tests := []struct {
+		name    string
+		opts    FileServerMkdirOptions
+		wantErr bool
+	}{
+		{name: "Create a directory", opts: FileServerMkdirOptions{Path: "/games/ni1_1", Name: "mods"}},
+		{name: "Missing name", opts: FileServerMkdirOptions{Path: "/games/ni1_1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.FileServerService.Mkdir(Service{ID: 7654321}, tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("FileServerService.Mkdir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestFileServerService_Bookmarks tests the FileServerService Bookmarks() and ResolveBookmark() methods.
+func TestFileServerService_Bookmarks(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/services/7654321/gameservers/file_server/bookmarks", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"bookmarks":["/games/ni1_1/noftp/dayzxb","/games/ni1_1/noftp/dayzxb/config","/games/ni1_1/noftp/dayzxb/mpmissions","/games/ni1_1/noftp/dayzxb/profiles/Logs"]}}`)
+	})
+
+	svc := Service{ID: 7654321}
+	got, _, err := client.FileServerService.Bookmarks(svc)
+	if err != nil {
+		t.Fatalf("FileServerService.Bookmarks() error = %v", err)
+	}
+	if len(got) != 4 {
+		t.Errorf("FileServerService.Bookmarks() got %d bookmarks, want 4", len(got))
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "config", want: "/games/ni1_1/noftp/dayzxb/config"},
+		{name: "MPMissions", want: "/games/ni1_1/noftp/dayzxb/mpmissions"},
+		{name: "log", want: "/games/ni1_1/noftp/dayzxb/profiles/Logs"},
+		{name: "noftp", want: "/games/ni1_1/noftp/dayzxb"},
+		{name: "mods", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := client.FileServerService.ResolveBookmark(svc, tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FileServerService.ResolveBookmark() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FileServerService.ResolveBookmark() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFileServerService_ListOptions tests the client side sorting and filtering options of the FileServerService List() method.
+func TestFileServerService_ListOptions(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	fsTestServer(t, mux, serverURL)
+
+	tests := []struct {
+		name string
+		opts FileServerListOptions
+		want []string
+	}{
+		{
+			name: "Default sort by modified",
+			opts: FileServerListOptions{Dir: "/games/ni1_1/config"},
+			want: []string{"b.ADM", "a.ADM"},
+		},
+		{
+			name: "Keep server order",
+			opts: FileServerListOptions{Dir: "/games/ni1_1", SortBy: FileSortNone},
+			want: []string{"config", "readme.txt"},
+		},
+		{
+			name: "Sort by name descending",
+			opts: FileServerListOptions{Dir: "/games/ni1_1/config", SortBy: FileSortName, SortDesc: true},
+			want: []string{"b.ADM", "a.ADM"},
+		},
+		{
+			name: "Sort by size",
+			opts: FileServerListOptions{Dir: "/games/ni1_1", SortBy: FileSortSize},
+			want: []string{"readme.txt", "config"},
+		},
+		{
+			name: "Sort by type",
+			opts: FileServerListOptions{Dir: "/games/ni1_1", SortBy: FileSortType, SortDesc: true},
+			want: []string{"readme.txt", "config"},
+		},
+		{
+			name: "Only directories",
+			opts: FileServerListOptions{Dir: "/games/ni1_1", Type: FileTypeDir},
+			want: []string{"config"},
+		},
+		{
+			name: "Name globs",
+			opts: FileServerListOptions{Dir: "/games/ni1_1/config", Names: []string{"a.*", "*.txt"}},
+			want: []string{"a.ADM"},
+		},
+		{
+			name: "Modified range",
+			opts: FileServerListOptions{Dir: "/games/ni1_1/config", ModifiedAfter: time.Unix(1608633680, 0), ModifiedBefore: time.Unix(1608633681, 0)},
+			want: []string{"a.ADM"},
+		},
+		{
+			name: "Recursive",
+			opts: FileServerListOptions{Dir: "/", Recursive: true, SortBy: FileSortName, Concurrency: 2},
+			want: []string{"a.ADM", "b.ADM", "config", "games", "ni1_1", "readme.txt"},
+		},
+		{
+			name: "Recursive files only",
+			opts: FileServerListOptions{Dir: "/games", Recursive: true, Type: FileTypeFile, SortBy: FileSortName},
+			want: []string{"a.ADM", "b.ADM", "readme.txt"},
+		},
+		{
+			name: "Search",
+			opts: FileServerListOptions{Dir: "/games/ni1_1", Search: "*.txt"},
+			want: []string{"readme.txt"},
+		},
+		{
+			name: "Recursive search",
+			opts: FileServerListOptions{Dir: "/games", Recursive: true, Search: "*.ADM", SortBy: FileSortName},
+			want: []string{"a.ADM", "b.ADM"},
+		},
+		{
+			name: "Recursive order by path",
+			opts: FileServerListOptions{Dir: "/", Recursive: true, SortBy: FileSortNone},
+			want: []string{"games", "ni1_1", "config", "a.ADM", "b.ADM", "readme.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, _, err := client.FileServerService.List(Service{ID: 7654321}, tt.opts)
+			if err != nil {
+				t.Fatalf("FileServerService.List() error = %v", err)
+			}
+			got := make([]string, 0, len(files))
+			for _, f := range files {
+				got = append(got, f.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FileServerService.List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFileServerService_error tests that the file server methods return API errors.
+func TestFileServerService_error(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	svc := Service{ID: 7654321}
+
+	for _, name := range []string{"list", "bookmarks", "download"} {
+		mux.HandleFunc("/services/7654321/gameservers/file_server/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"status":"error","message":"Directory not found"}`)
+		})
+	}
+
+	const want = `status "error" ("Directory not found")`
+	if _, _, err := client.FileServerService.List(svc, FileServerListOptions{Dir: "/missing"}); err == nil || err.Error() != want {
+		t.Errorf("List returned error %v, want %s", err, want)
+	}
+	if _, _, err := client.FileServerService.Bookmarks(svc); err == nil || err.Error() != want {
+		t.Errorf("Bookmarks returned error %v, want %s", err, want)
+	}
+	if _, _, err := client.FileServerService.Download(svc, FileServerDownloadOptions{File: "/missing"}); err == nil || err.Error() != want {
+		t.Errorf("Download returned error %v, want %s", err, want)
+	}
+}
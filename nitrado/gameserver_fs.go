@@ -0,0 +1,283 @@
+package nitrado
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// GameServerFS provides read only access to the file system of a GameServer
+// using the FileServerService. It implements fs.FS, fs.ReadDirFS, fs.StatFS
+// and fs.ReadFileFS, so it can be used with fs.WalkDir, fs.Glob and friends.
+//
+// Paths are relative to the root of the file server, so the absolute path
+// "/games/ni1_1/noftp" is opened as "games/ni1_1/noftp". Use fs.Sub to
+// root the file system at a different directory.
+type GameServerFS struct {
+	client *Client
+	svc    Service
+
+	// CacheTTL controls how long directory listings are cached for. A zero
+	// value disables the cache, so every call hits the Nitrado API.
+	CacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]fsCacheEntry
+}
+
+type fsCacheEntry struct {
+	files   []File
+	expires time.Time
+}
+
+// FS returns a file system for the GameServer attached to the given service.
+func FS(c *Client, svc Service) *GameServerFS {
+	return &GameServerFS{
+		client: c,
+		svc:    svc,
+		cache:  make(map[string]fsCacheEntry),
+	}
+}
+
+// Open opens the named file or directory.
+func (f *GameServerFS) Open(name string) (fs.File, error) {
+	info, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &gameServerDir{fsys: f, name: name, info: info}, nil
+	}
+
+	return &gameServerFile{fsys: f, name: name, info: info}, nil
+}
+
+// Stat returns a FileInfo describing the named file.
+func (f *GameServerFS) Stat(name string) (fs.FileInfo, error) {
+	return f.stat("stat", name)
+}
+
+// ReadDir reads the named directory and returns its entries sorted by filename.
+func (f *GameServerFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	files, err := f.list(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(files))
+	for _, file := range files {
+		entries = append(entries, fs.FileInfoToDirEntry(fileInfo{file}))
+	}
+
+	return entries, nil
+}
+
+// ReadFile downloads the named file and returns its contents.
+func (f *GameServerFS) ReadFile(name string) ([]byte, error) {
+	info, err := f.stat("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+
+	body, _, err := f.client.FileServerService.fetch(f.svc, remotePath(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+// ClearCache drops all cached directory listings.
+func (f *GameServerFS) ClearCache() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cache = make(map[string]fsCacheEntry)
+}
+
+// stat looks up the named file in the listing of its parent directory.
+func (f *GameServerFS) stat(op, name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return rootInfo{}, nil
+	}
+
+	files, err := f.list(path.Dir(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	base := path.Base(name)
+	i := sort.Search(len(files), func(i int) bool { return files[i].Name >= base })
+	if i == len(files) || files[i].Name != base {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return fileInfo{files[i]}, nil
+}
+
+// list returns the entries of the named directory sorted by name, using the
+// listing cache when it is enabled.
+func (f *GameServerFS) list(name string) ([]File, error) {
+	f.mu.Lock()
+	entry, ok := f.cache[name]
+	f.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.files, nil
+	}
+
+	files, _, err := f.client.FileServerService.List(f.svc, FileServerListOptions{Dir: remotePath(name), SortBy: FileSortName})
+	if err != nil {
+		return nil, err
+	}
+
+	if f.CacheTTL > 0 {
+		f.mu.Lock()
+		f.cache[name] = fsCacheEntry{files: files, expires: time.Now().Add(f.CacheTTL)}
+		f.mu.Unlock()
+	}
+
+	return files, nil
+}
+
+// remotePath converts an fs.FS path into an absolute file server path.
+func remotePath(name string) string {
+	if name == "." {
+		return "/"
+	}
+	return "/" + name
+}
+
+// fileInfo wraps a File to satisfy the fs.FileInfo interface.
+type fileInfo struct {
+	file File
+}
+
+func (fi fileInfo) Name() string       { return fi.file.Name }
+func (fi fileInfo) Size() int64        { return int64(fi.file.Size) }
+func (fi fileInfo) ModTime() time.Time { return time.Unix(int64(fi.file.ModifiedAt), 0) }
+func (fi fileInfo) IsDir() bool        { return fi.Mode().IsDir() }
+func (fi fileInfo) Sys() interface{}   { return fi.file }
+
+func (fi fileInfo) Mode() fs.FileMode {
+	var mode fs.FileMode
+	if perm, err := strconv.ParseUint(fi.file.Chmod, 8, 32); err == nil {
+		mode = fs.FileMode(perm) & fs.ModePerm
+	}
+	if fi.file.Type == FileTypeDir {
+		mode |= fs.ModeDir
+	}
+	return mode
+}
+
+// rootInfo describes the root directory of the file server, which never
+// appears in a listing.
+type rootInfo struct{}
+
+func (rootInfo) Name() string       { return "." }
+func (rootInfo) Size() int64        { return 0 }
+func (rootInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (rootInfo) ModTime() time.Time { return time.Time{} }
+func (rootInfo) IsDir() bool        { return true }
+func (rootInfo) Sys() interface{}   { return nil }
+
+// gameServerFile is an open file on a GameServer. The contents are only
+// downloaded on the first call to Read.
+type gameServerFile struct {
+	fsys   *GameServerFS
+	name   string
+	info   fs.FileInfo
+	reader *bytes.Reader
+	closed bool
+}
+
+func (f *gameServerFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *gameServerFile) Read(b []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.reader == nil {
+		data, err := f.fsys.ReadFile(f.name)
+		if err != nil {
+			return 0, err
+		}
+		f.reader = bytes.NewReader(data)
+	}
+	return f.reader.Read(b)
+}
+
+func (f *gameServerFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+// gameServerDir is an open directory on a GameServer.
+type gameServerDir struct {
+	fsys    *GameServerFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+	loaded  bool
+}
+
+func (d *gameServerDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *gameServerDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+func (d *gameServerDir) Close() error {
+	return nil
+}
+
+// ReadDir follows the semantics of fs.ReadDirFile.
+func (d *gameServerDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.loaded = true
+	}
+
+	remaining := len(d.entries) - d.offset
+	if n <= 0 {
+		entries := d.entries[d.offset:]
+		d.offset = len(d.entries)
+		return entries, nil
+	}
+	if remaining == 0 {
+		return nil, io.EOF
+	}
+	if n > remaining {
+		n = remaining
+	}
+	entries := d.entries[d.offset : d.offset+n]
+	d.offset += n
+	return entries, nil
+}
@@ -0,0 +1,163 @@
+package nitrado
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fsTestServer registers list and download handlers for a small file tree on mux.
+func fsTestServer(t *testing.T, mux *http.ServeMux, serverURL string) *atomic.Int32 {
+	listCalls := &atomic.Int32{}
+	listings := map[string]string{
+		"/":                   `{"status":"success","data":{"entries":[{"owner":"ni1_1","chmod":"40775","size":4096,"path":"/games","type":"dir","modified_at":1608633679,"name":"games"}]}}`,
+		"/games":              `{"status":"success","data":{"entries":[{"owner":"ni1_1","chmod":"40775","size":4096,"path":"/games/ni1_1","type":"dir","modified_at":1608633679,"name":"ni1_1"}]}}`,
+		"/games/ni1_1":        `{"status":"success","data":{"entries":[{"owner":"ni1_1","chmod":"40775","size":4096,"path":"/games/ni1_1/config","type":"dir","modified_at":1608633679,"name":"config"},{"owner":"ni1_1","chmod":"100664","size":5,"path":"/games/ni1_1/readme.txt","type":"file","modified_at":1608633600,"name":"readme.txt"}]}}`,
+		"/games/ni1_1/config": `{"status":"success","data":{"entries":[{"owner":"ni1_1","chmod":"100664","size":11,"path":"/games/ni1_1/config/b.ADM","type":"file","modified_at":1608633679,"name":"b.ADM"},{"owner":"ni1_1","chmod":"100664","size":11,"path":"/games/ni1_1/config/a.ADM","type":"file","modified_at":1608633680,"name":"a.ADM"}]}}`,
+	}
+	contents := map[string]string{
+		"/games/ni1_1/readme.txt":   "hello",
+		"/games/ni1_1/config/a.ADM": "a log line\n",
+		"/games/ni1_1/config/b.ADM": "b log line\n",
+	}
+
+	mux.HandleFunc("/services/7654321/gameservers/file_server/list", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		listCalls.Add(1)
+		listing, ok := listings[r.URL.Query().Get("dir")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"status":"error","message":"Directory not found"}`)
+			return
+		}
+		// Like the Nitrado API, a search hides every entry whose name does
+		// not match, including directories.
+		if search := r.URL.Query().Get("search"); search != "" {
+			var resp FileListResp
+			require.Nil(t, json.Unmarshal([]byte(listing), &resp))
+			var entries []File
+			for _, e := range resp.Data.Entries {
+				if ok, _ := path.Match(search, e.Name); ok {
+					entries = append(entries, e)
+				}
+			}
+			resp.Data.Entries = entries
+			_ = json.NewEncoder(w).Encode(resp)
+			return
+		}
+		_, _ = fmt.Fprint(w, listing)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/file_server/download", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"token":{"url":"%s%s/download?file=%s","token":"00000000-0000-0000-0000-000000000000"}}}`, serverURL, baseURLPath, r.URL.Query().Get("file"))
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		content, ok := contents[r.URL.Query().Get("file")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, content)
+	})
+
+	return listCalls
+}
+
+// TestGameServerFS tests the GameServerFS against the fstest conformance checks.
+func TestGameServerFS(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	fsTestServer(t, mux, serverURL)
+
+	fsys := FS(client, Service{ID: 7654321})
+	err := fstest.TestFS(fsys, "games/ni1_1/readme.txt", "games/ni1_1/config/a.ADM", "games/ni1_1/config/b.ADM")
+	require.Nil(t, err)
+}
+
+// TestGameServerFS_ReadFile tests the GameServerFS ReadFile() method.
+func TestGameServerFS_ReadFile(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	fsTestServer(t, mux, serverURL)
+
+	fsys := FS(client, Service{ID: 7654321})
+
+	got, err := fs.ReadFile(fsys, "games/ni1_1/readme.txt")
+	require.Nil(t, err)
+	assert.Equal(t, "hello", string(got))
+
+	_, err = fs.ReadFile(fsys, "games/ni1_1/missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = fs.ReadFile(fsys, "games/ni1_1/config")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+
+	_, err = fs.ReadFile(fsys, "/games/ni1_1/readme.txt")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+// TestGameServerFS_Stat tests the GameServerFS Stat() method.
+func TestGameServerFS_Stat(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	fsTestServer(t, mux, serverURL)
+
+	fsys := FS(client, Service{ID: 7654321})
+
+	info, err := fs.Stat(fsys, "games/ni1_1/config/a.ADM")
+	require.Nil(t, err)
+	assert.Equal(t, "a.ADM", info.Name())
+	assert.Equal(t, int64(11), info.Size())
+	assert.Equal(t, fs.FileMode(0o664), info.Mode())
+	assert.Equal(t, time.Unix(1608633680, 0), info.ModTime())
+	assert.Equal(t, "/games/ni1_1/config/a.ADM", info.Sys().(File).Path)
+
+	info, err = fs.Stat(fsys, "games/ni1_1/config")
+	require.Nil(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, fs.ModeDir|0o775, info.Mode())
+}
+
+// TestGameServerFS_Glob tests that fs.Glob works against a GameServerFS.
+func TestGameServerFS_Glob(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	fsTestServer(t, mux, serverURL)
+
+	sub, err := fs.Sub(FS(client, Service{ID: 7654321}), "games/ni1_1")
+	require.Nil(t, err)
+
+	got, err := fs.Glob(sub, "config/*.ADM")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"config/a.ADM", "config/b.ADM"}, got)
+}
+
+// TestGameServerFS_Cache tests that directory listings are cached when CacheTTL is set.
+func TestGameServerFS_Cache(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	listCalls := fsTestServer(t, mux, serverURL)
+
+	fsys := FS(client, Service{ID: 7654321})
+	fsys.CacheTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		_, err := fsys.ReadDir("games/ni1_1/config")
+		require.Nil(t, err)
+	}
+	assert.Equal(t, int32(1), listCalls.Load())
+
+	fsys.ClearCache()
+	_, err := fsys.ReadDir("games/ni1_1/config")
+	require.Nil(t, err)
+	assert.Equal(t, int32(2), listCalls.Load())
+}
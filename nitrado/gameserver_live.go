@@ -0,0 +1,275 @@
+package nitrado
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultLiveMinBackoff = time.Second
+	defaultLiveMaxBackoff = time.Minute
+	defaultLiveBuffer     = 64
+	liveHandshakeTimeout  = 10 * time.Second
+)
+
+// LiveService provides live events of a service over the Nitrado websocket.
+//
+// Nitrado API docs: https://doc.nitrado.net/
+type LiveService apiService
+
+// ErrLiveAuth is returned, or sent in a LiveDisconnected event, when the
+// websocket rejects the token of a service. The LiveService does not
+// reconnect after it.
+var ErrLiveAuth = errors.New("websocket authentication failed")
+
+// LiveEventType identifies the kind of a LiveEvent.
+type LiveEventType string
+
+// The events sent by the LiveService. Messages of other types are passed on
+// with their type and Raw data.
+const (
+	LiveConnected    LiveEventType = "connected"    // The websocket was connected and authenticated.
+	LiveDisconnected LiveEventType = "disconnected" // The websocket was lost, Err holds the reason.
+	LiveConsole      LiveEventType = "console"      // A line of console output.
+	LiveStatus       LiveEventType = "status"       // The status of the game server changed.
+	LivePlayer       LiveEventType = "player"       // A player joined or left.
+)
+
+// LiveEvent is an event received from the Nitrado websocket.
+type LiveEvent struct {
+	Type LiveEventType
+	Time time.Time
+
+	Line        string          // The console output, for LiveConsole events.
+	Status      string          // The new status, such as "started" or "stopped", for LiveStatus events.
+	Player      Player          // The player, for LivePlayer events.
+	PlayerEvent PlayerEventType // PlayerJoined or PlayerLeft, for LivePlayer events.
+	Err         error           // The reason for LiveDisconnected events.
+
+	Raw json.RawMessage // The data of the message.
+}
+
+// LiveOptions controls the connection to the Nitrado websocket.
+type LiveOptions struct {
+	MinBackoff time.Duration // The delay before the first reconnection attempt, defaults to 1 second.
+	MaxBackoff time.Duration // The maximum delay between reconnection attempts, defaults to 1 minute.
+	Buffer     int           // The size of the events channel buffer, defaults to 64.
+}
+
+// liveAuth is the message which authenticates a websocket connection.
+type liveAuth struct {
+	Action    string `json:"action"`
+	ServiceID int    `json:"service_id"`
+	Token     string `json:"token"`
+}
+
+// liveMessage is a message received from the websocket.
+type liveMessage struct {
+	Type    string          `json:"type"`
+	Status  string          `json:"status,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Time    int64           `json:"time,omitempty"` // Unix time of the event.
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Connect opens the websocket of a service using its WebsocketToken, with the
+// default options. See ConnectWithOptions.
+func (s *LiveService) Connect(ctx context.Context, svc Service) (<-chan LiveEvent, error) {
+	return s.ConnectWithOptions(ctx, svc, LiveOptions{})
+}
+
+// ConnectWithOptions opens the websocket of a service using its
+// WebsocketToken. An error is returned if the first connection fails.
+// After that, events are sent on the returned channel, and lost connections
+// are reported with a LiveDisconnected event and reconnected with an
+// exponential backoff. The channel is closed when ctx is cancelled, or after
+// the websocket rejects the token.
+func (s *LiveService) ConnectWithOptions(ctx context.Context, svc Service, opts LiveOptions) (<-chan LiveEvent, error) {
+	if svc.WebsocketToken == "" {
+		return nil, fmt.Errorf("service %d has no websocket token", svc.ID)
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultLiveMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = defaultLiveMaxBackoff
+		if opts.MaxBackoff < opts.MinBackoff {
+			opts.MaxBackoff = opts.MinBackoff
+		}
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = defaultLiveBuffer
+	}
+
+	conn, err := s.dial(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan LiveEvent, opts.Buffer)
+	go s.run(ctx, svc, opts, conn, events)
+	return events, nil
+}
+
+// run reads events from conn, reconnecting whenever the connection is lost.
+func (s *LiveService) run(ctx context.Context, svc Service, opts LiveOptions, conn *websocket.Conn, events chan<- LiveEvent) {
+	defer close(events)
+
+	send := func(e LiveEvent) bool {
+		select {
+		case events <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		if !send(LiveEvent{Type: LiveConnected, Time: time.Now()}) {
+			conn.Close()
+			return
+		}
+		err := s.read(ctx, conn, send)
+		if ctx.Err() != nil {
+			return
+		}
+		if !send(LiveEvent{Type: LiveDisconnected, Time: time.Now(), Err: err}) {
+			return
+		}
+
+		backoff := opts.MinBackoff
+		for {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			conn, err = s.dial(ctx, svc)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if !send(LiveEvent{Type: LiveDisconnected, Time: time.Now(), Err: err}) || errors.Is(err, ErrLiveAuth) {
+				return
+			}
+			backoff *= 2
+			if backoff > opts.MaxBackoff {
+				backoff = opts.MaxBackoff
+			}
+		}
+	}
+}
+
+// dial connects to the websocket and authenticates with the token of svc.
+func (s *LiveService) dial(ctx context.Context, svc Service) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: liveHandshakeTimeout, Proxy: http.ProxyFromEnvironment}
+	header := http.Header{}
+	header.Set("User-Agent", s.client.UserAgent)
+
+	conn, _, err := dialer.DialContext(ctx, s.client.LiveURI.String(), header)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(liveHandshakeTimeout))
+	if err := conn.WriteJSON(liveAuth{Action: "auth", ServiceID: svc.ID, Token: svc.WebsocketToken}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(liveHandshakeTimeout))
+	var msg liveMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if msg.Type != "auth" || msg.Status != "success" {
+		conn.Close()
+		return nil, fmt.Errorf("%w: status %q (%q)", ErrLiveAuth, msg.Status, msg.Message)
+	}
+	_ = conn.SetWriteDeadline(time.Time{})
+	_ = conn.SetReadDeadline(time.Time{})
+
+	return conn, nil
+}
+
+// read sends the events received on conn until the connection fails or ctx
+// is cancelled, and returns the reason.
+func (s *LiveService) read(ctx context.Context, conn *websocket.Conn, send func(LiveEvent) bool) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var msg liveMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		e, ok := liveEvent(msg)
+		if !ok {
+			continue
+		}
+		if !send(e) {
+			return ctx.Err()
+		}
+	}
+}
+
+// liveEvent converts a websocket message into an event.
+func liveEvent(msg liveMessage) (LiveEvent, bool) {
+	if msg.Type == "" || msg.Type == "auth" {
+		return LiveEvent{}, false
+	}
+
+	e := LiveEvent{Type: LiveEventType(msg.Type), Time: time.Now(), Raw: msg.Data}
+	if msg.Time > 0 {
+		e.Time = time.Unix(msg.Time, 0)
+	}
+
+	switch msg.Type {
+	case string(LiveConsole):
+		var data struct {
+			Line string `json:"line"`
+		}
+		_ = json.Unmarshal(msg.Data, &data)
+		e.Line = data.Line
+	case string(LiveStatus):
+		var data struct {
+			Status string `json:"status"`
+		}
+		_ = json.Unmarshal(msg.Data, &data)
+		e.Status = data.Status
+	case string(LivePlayer):
+		var data struct {
+			Event  PlayerEventType `json:"event"`
+			Player Player          `json:"player"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return LiveEvent{}, false
+		}
+		e.PlayerEvent = data.Event
+		e.Player = data.Player
+	}
+
+	return e, true
+}
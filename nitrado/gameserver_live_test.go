@@ -0,0 +1,176 @@
+package nitrado
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveTestServer is a stand-in for the Nitrado websocket. Authenticated
+// connections are sent on conns so that tests can write messages to them.
+type liveTestServer struct {
+	mu    sync.Mutex
+	token string
+	conns chan *websocket.Conn
+}
+
+func newLiveTestServer(t *testing.T, client *Client, token string) *liveTestServer {
+	s := &liveTestServer{token: token, conns: make(chan *websocket.Conn, 10)}
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.Nil(t, err) {
+			return
+		}
+		var auth liveAuth
+		if err := conn.ReadJSON(&auth); err != nil {
+			conn.Close()
+			return
+		}
+		assert.Equal(t, "auth", auth.Action)
+		assert.Equal(t, 7654321, auth.ServiceID)
+
+		s.mu.Lock()
+		ok := auth.Token == s.token
+		s.mu.Unlock()
+		if !ok {
+			_ = conn.WriteJSON(liveMessage{Type: "auth", Status: "error", Message: "Invalid token"})
+			conn.Close()
+			return
+		}
+		_ = conn.WriteJSON(liveMessage{Type: "auth", Status: "success"})
+		s.conns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse("ws" + strings.TrimPrefix(server.URL, "http") + "/")
+	client.LiveURI = u
+	return s
+}
+
+func (s *liveTestServer) setToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// accept returns the next authenticated connection.
+func (s *liveTestServer) accept(t *testing.T) *websocket.Conn {
+	t.Helper()
+	select {
+	case conn := <-s.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a websocket connection")
+		return nil
+	}
+}
+
+// nextLiveEvent returns the next event, failing the test when there is none.
+func nextLiveEvent(t *testing.T, events <-chan LiveEvent) LiveEvent {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		require.True(t, ok, "events channel closed")
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a live event")
+		return LiveEvent{}
+	}
+}
+
+// TestLiveService_Connect tests the LiveService Connect() method.
+func TestLiveService_Connect(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+	server := newLiveTestServer(t, client, "abcdefgh012345")
+	svc := Service{ID: 7654321, WebsocketToken: "abcdefgh012345"}
+
+	_, err := client.Live.Connect(context.Background(), Service{ID: 7654321})
+	assert.Error(t, err)
+	_, err = client.Live.Connect(context.Background(), Service{ID: 7654321, WebsocketToken: "wrong"})
+	assert.ErrorIs(t, err, ErrLiveAuth)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Live.ConnectWithOptions(ctx, svc, LiveOptions{MinBackoff: 10 * time.Millisecond})
+	require.Nil(t, err)
+	conn := server.accept(t)
+	assert.Equal(t, LiveConnected, nextLiveEvent(t, events).Type)
+
+	require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"console","time":1608633679,"data":{"line":"Player \"Survivor\" is connected"}}`)))
+	require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`not json`)))
+	require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"status","data":{"status":"restarting"}}`)))
+	require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"player","data":{"event":"joined","player":{"name":"Survivor","id":"76561197960287930","id_type":"steamid","online":true}}}`)))
+	require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"backup","data":{"progress":50}}`)))
+
+	e := nextLiveEvent(t, events)
+	assert.Equal(t, LiveConsole, e.Type)
+	assert.Equal(t, `Player "Survivor" is connected`, e.Line)
+	assert.Equal(t, time.Unix(1608633679, 0), e.Time)
+
+	e = nextLiveEvent(t, events)
+	assert.Equal(t, LiveStatus, e.Type)
+	assert.Equal(t, "restarting", e.Status)
+
+	e = nextLiveEvent(t, events)
+	assert.Equal(t, LivePlayer, e.Type)
+	assert.Equal(t, PlayerJoined, e.PlayerEvent)
+	assert.Equal(t, Player{Name: "Survivor", ID: "76561197960287930", IDType: PlayerIDSteam, Online: true}, e.Player)
+
+	e = nextLiveEvent(t, events)
+	assert.Equal(t, LiveEventType("backup"), e.Type)
+	assert.JSONEq(t, `{"progress":50}`, string(e.Raw))
+
+	// A lost connection is reported and reconnected.
+	conn.Close()
+	e = nextLiveEvent(t, events)
+	assert.Equal(t, LiveDisconnected, e.Type)
+	assert.Error(t, e.Err)
+	server.accept(t)
+	assert.Equal(t, LiveConnected, nextLiveEvent(t, events).Type)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events channel was not closed")
+	}
+}
+
+// TestLiveService_ConnectAuthRevoked tests that the LiveService stops reconnecting when the token is rejected.
+func TestLiveService_ConnectAuthRevoked(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+	server := newLiveTestServer(t, client, "abcdefgh012345")
+	svc := Service{ID: 7654321, WebsocketToken: "abcdefgh012345"}
+
+	events, err := client.Live.ConnectWithOptions(context.Background(), svc, LiveOptions{MinBackoff: 10 * time.Millisecond})
+	require.Nil(t, err)
+	conn := server.accept(t)
+	assert.Equal(t, LiveConnected, nextLiveEvent(t, events).Type)
+
+	server.setToken("renewed")
+	conn.Close()
+	assert.Equal(t, LiveDisconnected, nextLiveEvent(t, events).Type)
+	e := nextLiveEvent(t, events)
+	assert.Equal(t, LiveDisconnected, e.Type)
+	assert.ErrorIs(t, e.Err, ErrLiveAuth)
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events channel was not closed")
+	}
+}
@@ -0,0 +1,274 @@
+package nitrado
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTailInterval = 10 * time.Second // Used when LogTailer.Interval is not set.
+	maxTailBackoff      = 5 * time.Minute  // The longest wait between retries of a failing poll.
+)
+
+// logTimeRe matches the time a log file was started in its name, such as
+// "DayZServer_x64_2021-06-01_13-48-55.ADM" or
+// "DayZServer_X1_x64_2022_05_20_083532573.ADM".
+var logTimeRe = regexp.MustCompile(`(\d{4})[-_](\d{2})[-_](\d{2})(?:_(\d{2})-?(\d{2})-?(\d{2})(\d{3})?)?`)
+
+// LogCheckpoint records how far a LogTailer has read, so that following can
+// resume after a restart.
+type LogCheckpoint struct {
+	Path   string `json:"path"`
+	Offset int64  `json:"offset"`
+}
+
+// LogLine is a single line read from a log file on a GameServer.
+type LogLine struct {
+	Path    string    // Absolute path of the log file.
+	Offset  int64     // Byte offset just past the end of this line.
+	Text    string    // The line without its trailing line ending.
+	Started time.Time // When the log file was started, from its name. Zero when the name has no time.
+}
+
+// Checkpoint returns the position to resume from once this line has been processed.
+func (l LogLine) Checkpoint() LogCheckpoint {
+	return LogCheckpoint{Path: l.Path, Offset: l.Offset}
+}
+
+// LogTailer follows the newest log file in a directory on a GameServer,
+// similar to "tail -f". New data is read using range downloads, and when a
+// newer file matching Pattern appears the tailer finishes the current file
+// and moves on to it. Files are ordered by the time in their name, like the
+// admin logs of DayZ, and by modification time when their name has none.
+type LogTailer struct {
+	client *Client
+	svc    Service
+
+	Dir      string         // Directory containing the log files.
+	Pattern  string         // path.Match pattern for the log files, e.g. "*.ADM".
+	Interval time.Duration  // How often to poll for new data, defaults to 10 seconds.
+	Location *time.Location // Time zone of the times in the log file names, defaults to UTC.
+
+	// Checkpoint is the position to start following from. When it is empty
+	// the newest file is followed from its current end, or from the start if
+	// FromStart is set.
+	Checkpoint LogCheckpoint
+	FromStart  bool
+
+	// OnError is called when a poll fails. The tailer keeps its position and
+	// tries again, waiting twice as long after each failure, up to 5
+	// minutes.
+	OnError func(error)
+
+	mu  sync.Mutex
+	pos LogCheckpoint
+}
+
+// NewLogTailer returns a LogTailer for the log files matching pattern in dir.
+func NewLogTailer(c *Client, svc Service, dir, pattern string) *LogTailer {
+	return &LogTailer{
+		client:  c,
+		svc:     svc,
+		Dir:     dir,
+		Pattern: pattern,
+	}
+}
+
+// Position returns the checkpoint of the last line sent by Run.
+func (t *LogTailer) Position() LogCheckpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pos
+}
+
+// Run follows the log files and sends each complete line to lines until ctx
+// is cancelled. Lines are only sent once they end with a newline. Run does
+// not close lines.
+func (t *LogTailer) Run(ctx context.Context, lines chan<- LogLine) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = defaultTailInterval
+	}
+
+	t.setPosition(t.Checkpoint)
+	started := t.Checkpoint.Path != ""
+
+	wait := interval
+	for {
+		err := t.tail(ctx, &started, lines)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			if t.OnError != nil {
+				t.OnError(err)
+			}
+			if wait *= 2; wait > maxTailBackoff {
+				wait = maxTailBackoff
+			}
+		default:
+			wait = interval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// tail lists the log files and reads any new lines, starting on the newest
+// file when the tailer has not started yet.
+func (t *LogTailer) tail(ctx context.Context, started *bool, lines chan<- LogLine) error {
+	files, _, err := t.client.FileServerService.List(t.svc, FileServerListOptions{
+		Dir:   t.Dir,
+		Type:  FileTypeFile,
+		Names: []string{t.Pattern},
+	})
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return logFileTime(files[i]).Before(logFileTime(files[j]))
+	})
+
+	if !*started {
+		newest := files[len(files)-1]
+		pos := LogCheckpoint{Path: newest.Path}
+		if !t.FromStart {
+			pos.Offset = int64(newest.Size)
+		}
+		t.setPosition(pos)
+		*started = true
+	}
+	return t.poll(ctx, files, lines)
+}
+
+// poll reads any new data from the current file and from every file that is
+// newer than it. files must be sorted by logFileTime.
+func (t *LogTailer) poll(ctx context.Context, files []File, lines chan<- LogLine) error {
+	pos := t.Position()
+
+	current := -1
+	for i, f := range files {
+		if f.Path == pos.Path {
+			current = i
+			break
+		}
+	}
+
+	if current == -1 {
+		// The file we were following has gone. Carry on with the files
+		// started after it, or with the newest one when its name has no time.
+		started, ok := nameTime(pos.Path, time.UTC)
+		current = len(files) - 1
+		if ok {
+			current = sort.Search(len(files), func(i int) bool {
+				return logFileTime(files[i]).After(started)
+			})
+			if current == len(files) {
+				return nil
+			}
+		}
+		pos = LogCheckpoint{Path: files[current].Path}
+	}
+	if int64(files[current].Size) < pos.Offset {
+		// The file has been truncated or replaced in place.
+		pos.Offset = 0
+	}
+
+	if err := t.read(ctx, pos, int64(files[current].Size), lines); err != nil {
+		return err
+	}
+	for _, f := range files[current+1:] {
+		if err := t.read(ctx, LogCheckpoint{Path: f.Path}, int64(f.Size), lines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// read sends the complete lines in the file at pos, up to size bytes. An
+// incomplete final line is left to be read again by the next poll.
+func (t *LogTailer) read(ctx context.Context, pos LogCheckpoint, size int64, lines chan<- LogLine) error {
+	t.setPosition(pos)
+	if size <= pos.Offset {
+		return nil
+	}
+
+	body, _, err := t.client.FileServerService.fetchFrom(t.svc, pos.Path, pos.Offset)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, size-pos.Offset))
+	if err != nil {
+		return err
+	}
+
+	loc := t.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	started, _ := nameTime(pos.Path, loc)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil
+		}
+		pos.Offset += int64(i + 1)
+		line := LogLine{Path: pos.Path, Offset: pos.Offset, Text: string(bytes.TrimRight(data[:i], "\r")), Started: started}
+		data = data[i+1:]
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case lines <- line:
+		}
+		t.setPosition(pos)
+	}
+}
+
+func (t *LogTailer) setPosition(pos LogCheckpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pos = pos
+}
+
+// logFileTime returns the time the log file was started from its name, or
+// its modification time when the name has none.
+func logFileTime(f File) time.Time {
+	if t, ok := nameTime(f.Path, time.UTC); ok {
+		return t
+	}
+	return time.Unix(int64(f.ModifiedAt), 0).UTC()
+}
+
+// nameTime parses the time in the name of a log file in the time zone loc.
+func nameTime(file string, loc *time.Location) (time.Time, bool) {
+	m := logTimeRe.FindStringSubmatch(path.Base(file))
+	if m == nil {
+		return time.Time{}, false
+	}
+	n := make([]int, len(m))
+	for i, s := range m[1:] {
+		n[i+1], _ = strconv.Atoi(s)
+	}
+	t := time.Date(n[1], time.Month(n[2]), n[3], n[4], n[5], n[6], n[7]*int(time.Millisecond), loc)
+	return t, t.Month() == time.Month(n[2]) && t.Day() == n[3]
+}
@@ -0,0 +1,264 @@
+package nitrado
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogDir is a directory of log files which can be appended to while a LogTailer follows it.
+type fakeLogDir struct {
+	mu       sync.Mutex
+	clock    int
+	files    map[string]string
+	modified map[string]int
+	fail     int // Number of list requests to fail.
+}
+
+// write appends data to the named file, marking it as the most recently modified.
+func (d *fakeLogDir) write(name, data string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clock++
+	d.files[name] += data
+	d.modified[name] = 1608633600 + d.clock
+}
+
+// remove deletes the named file.
+func (d *fakeLogDir) remove(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.files, name)
+	delete(d.modified, name)
+}
+
+// replace overwrites the contents of the named file without changing its modification time.
+func (d *fakeLogDir) replace(name, data string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.files[name] = data
+}
+
+// register adds list and range download handlers for the directory to mux.
+func (d *fakeLogDir) register(t *testing.T, mux *http.ServeMux, serverURL string) {
+	mux.HandleFunc("/services/7654321/gameservers/file_server/list", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.fail > 0 {
+			d.fail--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = fmt.Fprint(w, `{"status":"error","message":"maintenance"}`)
+			return
+		}
+		var entries []File
+		for name, data := range d.files {
+			entries = append(entries, File{Name: name, Path: "/logs/" + name, Type: FileTypeFile, Size: len(data), ModifiedAt: d.modified[name]})
+		}
+		var resp FileListResp
+		resp.Status = "success"
+		resp.Data.Entries = entries
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/file_server/download", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"token":{"url":"%s%s/download?file=%s","token":"00000000-0000-0000-0000-000000000000"}}}`, serverURL, baseURLPath, r.URL.Query().Get("file"))
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		data := d.files[strings.TrimPrefix(r.URL.Query().Get("file"), "/logs/")]
+		d.mu.Unlock()
+		if rng := r.Header.Get("Range"); rng != "" {
+			offset, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if offset >= len(data) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			w.WriteHeader(http.StatusPartialContent)
+			data = data[offset:]
+		}
+		_, _ = fmt.Fprint(w, data)
+	})
+}
+
+// receive reads n lines from lines, failing the test if they do not arrive in time.
+func receive(t *testing.T, lines <-chan LogLine, n int) []LogLine {
+	t.Helper()
+	var got []LogLine
+	for len(got) < n {
+		select {
+		case l := <-lines:
+			got = append(got, l)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for log lines, got %v", got)
+		}
+	}
+	return got
+}
+
+// TestLogTailer_Run tests following a log file through appends and rotation.
+func TestLogTailer_Run(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+
+	dir := &fakeLogDir{files: map[string]string{}, modified: map[string]int{}}
+	dir.register(t, mux, serverURL)
+	dir.write("DayZServer_x64_2021_01_01.ADM", "old 1\nold 2\n")
+	dir.write("script.log", "ignored\n")
+
+	tailer := NewLogTailer(client, Service{ID: 7654321}, "/logs", "*.ADM")
+	tailer.Interval = 10 * time.Millisecond
+	tailer.FromStart = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan LogLine)
+	done := make(chan error)
+	go func() { done <- tailer.Run(ctx, lines) }()
+
+	got := receive(t, lines, 2)
+	assert.Equal(t, "old 1", got[0].Text)
+	assert.Equal(t, "old 2", got[1].Text)
+	assert.Equal(t, LogCheckpoint{Path: "/logs/DayZServer_x64_2021_01_01.ADM", Offset: 12}, got[1].Checkpoint())
+
+	// An incomplete line is held back until its newline is written.
+	dir.write("DayZServer_x64_2021_01_01.ADM", "old 3\r\npart")
+	got = receive(t, lines, 1)
+	assert.Equal(t, "old 3", got[0].Text)
+	dir.write("DayZServer_x64_2021_01_01.ADM", "ial\n")
+	got = receive(t, lines, 1)
+	assert.Equal(t, "partial", got[0].Text)
+
+	// A newer file is picked up once it appears.
+	dir.write("DayZServer_x64_2021_01_02.ADM", "new 1\n")
+	got = receive(t, lines, 1)
+	assert.Equal(t, LogLine{Path: "/logs/DayZServer_x64_2021_01_02.ADM", Offset: 6, Text: "new 1", Started: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)}, got[0])
+
+	// A file truncated in place is read again from the start.
+	dir.replace("DayZServer_x64_2021_01_02.ADM", "new\n")
+	got = receive(t, lines, 1)
+	assert.Equal(t, "new", got[0].Text)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, LogCheckpoint{Path: "/logs/DayZServer_x64_2021_01_02.ADM", Offset: 4}, tailer.Position())
+}
+
+// TestLogTailer_Checkpoint tests resuming from a checkpoint and tailing from the end of the newest file.
+func TestLogTailer_Checkpoint(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+
+	dir := &fakeLogDir{files: map[string]string{}, modified: map[string]int{}}
+	dir.register(t, mux, serverURL)
+	dir.write("a.ADM", "line 1\nline 2\n")
+
+	tests := []struct {
+		name       string
+		checkpoint LogCheckpoint
+		want       string
+	}{
+		{name: "Resume from checkpoint", checkpoint: LogCheckpoint{Path: "/logs/a.ADM", Offset: 7}, want: "line 2"},
+		{name: "Follow from end", want: "line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tailer := NewLogTailer(client, Service{ID: 7654321}, "/logs", "*.ADM")
+			tailer.Interval = 10 * time.Millisecond
+			tailer.Checkpoint = tt.checkpoint
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			lines := make(chan LogLine)
+			go func() { _ = tailer.Run(ctx, lines) }()
+
+			if tt.checkpoint.Path == "" {
+				// Wait for the tailer to start at the end of the file before writing.
+				require.Eventually(t, func() bool { return tailer.Position().Offset == 14 }, 5*time.Second, 5*time.Millisecond)
+				dir.write("a.ADM", "line 3\n")
+			}
+			got := receive(t, lines, 1)
+			assert.Equal(t, tt.want, got[0].Text)
+		})
+	}
+}
+
+// TestLogTailer_Rotation tests that files are followed in the order of the time in their names.
+func TestLogTailer_Rotation(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+
+	dir := &fakeLogDir{files: map[string]string{}, modified: map[string]int{}}
+	dir.register(t, mux, serverURL)
+	dir.write("DayZServer_X1_x64_2021_01_01_080000000.ADM", "first 1\n")
+	dir.write("DayZServer_X1_x64_2021_01_01_120000000.ADM", "second 1\n")
+	dir.write("DayZServer_X1_x64_2021_01_01_160000000.ADM", "third 1\n")
+
+	tailer := NewLogTailer(client, Service{ID: 7654321}, "/logs", "*.ADM")
+	tailer.Interval = 10 * time.Millisecond
+	tailer.Checkpoint = LogCheckpoint{Path: "/logs/DayZServer_X1_x64_2021_01_01_120000000.ADM", Offset: 9}
+	loc := time.FixedZone("AEST", 10*60*60)
+	tailer.Location = loc
+
+	// The checkpointed file is gone, so the tailer carries on with the next one.
+	dir.remove("DayZServer_X1_x64_2021_01_01_120000000.ADM")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lines := make(chan LogLine)
+	go func() { _ = tailer.Run(ctx, lines) }()
+
+	got := receive(t, lines, 1)
+	assert.Equal(t, "third 1", got[0].Text)
+	assert.Equal(t, time.Date(2021, 1, 1, 16, 0, 0, 0, loc), got[0].Started)
+
+	// An older log which is written to is not mistaken for the newest.
+	dir.write("DayZServer_X1_x64_2021_01_01_080000000.ADM", "first 2\n")
+	dir.write("DayZServer_X1_x64_2021_01_01_160000000.ADM", "third 2\n")
+	got = receive(t, lines, 1)
+	assert.Equal(t, "third 2", got[0].Text)
+}
+
+// TestLogTailer_Retry tests that the tailer keeps following after a failed poll.
+func TestLogTailer_Retry(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	client.Retries = 1
+
+	dir := &fakeLogDir{files: map[string]string{}, modified: map[string]int{}, fail: 2}
+	dir.register(t, mux, serverURL)
+	dir.write("a.ADM", "line 1\n")
+
+	var mu sync.Mutex
+	var errs []error
+	tailer := NewLogTailer(client, Service{ID: 7654321}, "/logs", "*.ADM")
+	tailer.Interval = time.Millisecond
+	tailer.FromStart = true
+	tailer.OnError = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan LogLine)
+	done := make(chan error)
+	go func() { done <- tailer.Run(ctx, lines) }()
+
+	got := receive(t, lines, 1)
+	assert.Equal(t, "line 1", got[0].Text)
+	mu.Lock()
+	assert.Len(t, errs, 2)
+	mu.Unlock()
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
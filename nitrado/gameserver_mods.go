@@ -0,0 +1,396 @@
+package nitrado
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ModsService manages the additionalMods setting of a DayZ GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Details
+type ModsService apiService
+
+// ErrModListChanged is returned by Save when the mod list was changed on the
+// server after it was read.
+var ErrModListChanged = errors.New("mod list changed since it was read")
+
+var (
+	workshopIDRe  = regexp.MustCompile(`^\d+$`)
+	publishedIDRe = regexp.MustCompile(`(?i)\bpublishedid\s*=\s*"?(\d+)`)
+)
+
+// Mod is an entry of a mod list, either a Steam workshop ID or the name of
+// a mod folder such as "@CF".
+type Mod struct {
+	WorkshopID string
+	Folder     string // The folder name, starting with "@" even when the entry does not.
+
+	entry string // The entry as written in the mod list.
+}
+
+// parseMod parses a single mod list entry.
+func parseMod(entry string) (Mod, bool) {
+	entry = strings.TrimSpace(entry)
+	switch {
+	case entry == "":
+		return Mod{}, false
+	case workshopIDRe.MatchString(entry):
+		return Mod{WorkshopID: entry, entry: entry}, true
+	case strings.HasPrefix(entry, "@"):
+		return Mod{Folder: entry, entry: entry}, true
+	default:
+		return Mod{Folder: "@" + entry, entry: entry}, true
+	}
+}
+
+// String returns the mod as written in the mod list.
+func (m Mod) String() string {
+	if m.entry != "" {
+		return m.entry
+	}
+	return m.name()
+}
+
+// name returns the workshop ID or folder name identifying the mod, however
+// its entry is written.
+func (m Mod) name() string {
+	if m.WorkshopID != "" {
+		return m.WorkshopID
+	}
+	return m.Folder
+}
+
+// folder returns the folder name the mod is expected in. Workshop mods are
+// often installed under the name of the mod instead, see Verify.
+func (m Mod) folder() string {
+	if m.Folder != "" {
+		return m.Folder
+	}
+	return "@" + m.WorkshopID
+}
+
+// ModList is the ordered list of mods loaded by a GameServer.
+//
+// Entries keep the text and separator they were read with, so a list which
+// is saved without changes keeps its value, and an edited list only changes
+// the edited entries. Blank and duplicate entries are dropped once the list
+// is edited.
+type ModList struct {
+	ServiceID int
+
+	original string
+	sep      string // The separator of entries in original.
+	edited   bool
+	mods     []Mod
+}
+
+// newModList parses a mod list separated by semicolons, commas or newlines,
+// ignoring blank entries and duplicates.
+func newModList(serviceID int, value string) *ModList {
+	l := &ModList{ServiceID: serviceID, original: value, sep: modSeparator(value)}
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == ',' || r == '\n' || r == '\r'
+	})
+	for _, f := range fields {
+		l.add(f)
+	}
+	return l
+}
+
+// modSeparator returns the first separator used in a mod list, or a
+// semicolon when there is none.
+func modSeparator(value string) string {
+	i := strings.IndexAny(value, ";,\r\n")
+	switch {
+	case i < 0:
+		return ";"
+	case strings.HasPrefix(value[i:], "\r\n"):
+		return "\r\n"
+	default:
+		return value[i : i+1]
+	}
+}
+
+// Mods returns the mods in load order.
+func (l *ModList) Mods() []Mod {
+	return append([]Mod(nil), l.mods...)
+}
+
+// Len returns the number of mods in the list.
+func (l *ModList) Len() int {
+	return len(l.mods)
+}
+
+// Contains reports whether the mod is in the list.
+func (l *ModList) Contains(entry string) bool {
+	return l.index(entry) >= 0
+}
+
+// Add adds a workshop ID or mod folder to the end of the list. It reports
+// false when the entry is blank or already in the list.
+func (l *ModList) Add(entry string) bool {
+	if !l.add(entry) {
+		return false
+	}
+	l.edited = true
+	return true
+}
+
+func (l *ModList) add(entry string) bool {
+	m, ok := parseMod(entry)
+	if !ok || l.Contains(m.String()) {
+		return false
+	}
+	l.mods = append(l.mods, m)
+	return true
+}
+
+// Remove removes a mod from the list. It reports false when the mod was not
+// in the list.
+func (l *ModList) Remove(entry string) bool {
+	i := l.index(entry)
+	if i < 0 {
+		return false
+	}
+	l.mods = append(l.mods[:i], l.mods[i+1:]...)
+	l.edited = true
+	return true
+}
+
+// Move moves a mod to position i in the load order, counting from 0. Mods
+// which depend on another mod, such as most mods on "@CF", must be loaded
+// after it.
+func (l *ModList) Move(entry string, i int) error {
+	from := l.index(entry)
+	if from < 0 {
+		return fmt.Errorf("mod %q is not in the list", entry)
+	}
+	if i < 0 || i >= len(l.mods) {
+		return fmt.Errorf("position %d is outside the mod list of length %d", i, len(l.mods))
+	}
+
+	m := l.mods[from]
+	l.mods = append(l.mods[:from], l.mods[from+1:]...)
+	l.mods = append(l.mods[:i], append([]Mod{m}, l.mods[i:]...)...)
+	l.edited = l.edited || from != i
+	return nil
+}
+
+// String returns the list in the format stored by Nitrado. An unedited list
+// returns the value it was read from.
+func (l *ModList) String() string {
+	if !l.edited {
+		return l.original
+	}
+	entries := make([]string, len(l.mods))
+	for i, m := range l.mods {
+		entries[i] = m.String()
+	}
+	return strings.Join(entries, l.sep)
+}
+
+// names returns the names of the mods in load order, for comparing lists
+// however their entries are written.
+func (l *ModList) names() string {
+	names := make([]string, len(l.mods))
+	for i, m := range l.mods {
+		names[i] = m.name()
+	}
+	return strings.Join(names, ";")
+}
+
+func (l *ModList) index(entry string) int {
+	m, ok := parseMod(entry)
+	if !ok {
+		return -1
+	}
+	for i, existing := range l.mods {
+		if strings.EqualFold(existing.name(), m.name()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// List returns the mods of a GameServer by service ID.
+func (s *ModsService) List(serviceID int) (*ModList, *http.Response, error) {
+	gs, resp, err := s.client.GameServers.Get(serviceID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	value, _ := gs.Settings.Value("general", "additionalMods")
+	return newModList(serviceID, value), resp, nil
+}
+
+// Save writes the mod list back to the GameServer in a single settings
+// update, unless another change was saved to the server since the list was
+// read, in which case ErrModListChanged is returned.
+func (s *ModsService) Save(l *ModList) error {
+	value := l.String()
+	sameMods := func(a, b string) bool {
+		return newModList(0, a).names() == newModList(0, b).names()
+	}
+	err := s.client.GameServersSettings.updateIfUnchanged(l.ServiceID, "additionalMods", l.original, value, sameMods)
+	if errors.Is(err, errSettingChanged) {
+		return ErrModListChanged
+	}
+	if err != nil {
+		return err
+	}
+	l.original = value
+	l.edited = false
+
+	return nil
+}
+
+// ModCheck is the result of verifying that a mod is installed.
+type ModCheck struct {
+	Mod          Mod
+	Folder       string   // The path of the mod folder.
+	FolderExists bool     // The mod folder exists.
+	Keys         []string // The .bikey files shipped in the keys folder of the mod.
+	MissingKeys  []string // Keys of the mod which are missing from the keys folder of the server.
+}
+
+// OK reports whether the mod folder exists and all of its keys are installed.
+func (c ModCheck) OK() bool {
+	return c.FolderExists && len(c.MissingKeys) == 0
+}
+
+// Verify checks that every mod in the list has a mod folder in dir on the
+// file server, and that the .bikey files in the keys folder of each mod are
+// also in the keys folder of dir, which the server needs to accept clients
+// using the mod. dir is the game directory, for example
+// "/games/ni1_1/noftp/dayzxb".
+//
+// A workshop mod is looked for in a folder named "@" followed by its ID, and
+// otherwise in the mod folder whose meta.cpp has the ID as its publishedid.
+// Only the meta.cpp of folders which no other mod in the list is named after
+// are read, and only until every workshop mod is found.
+func (s *ModsService) Verify(svc Service, l *ModList, dir string) ([]ModCheck, error) {
+	files := s.client.FileServerService
+	entries, _, err := files.List(svc, FileServerListOptions{Dir: dir, SortBy: FileSortName})
+	if err != nil {
+		return nil, err
+	}
+	folders := make(map[string]string)
+	for _, e := range entries {
+		if e.Type == FileTypeDir {
+			folders[strings.ToLower(e.Name)] = e.Path
+		}
+	}
+
+	serverKeys, err := s.bikeys(svc, folders["keys"])
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]bool)
+	for _, k := range serverKeys {
+		installed[strings.ToLower(k)] = true
+	}
+
+	claimed := make(map[string]bool)
+	wanted := make(map[string]bool)
+	for _, m := range l.mods {
+		if folder, ok := folders[strings.ToLower(m.folder())]; ok {
+			claimed[folder] = true
+		} else if m.WorkshopID != "" {
+			wanted[m.WorkshopID] = true
+		}
+	}
+	published := s.publishedIDs(svc, entries, claimed, wanted)
+
+	var checks []ModCheck
+	for _, m := range l.mods {
+		c := ModCheck{Mod: m, Folder: path.Join(dir, m.folder())}
+		folder, ok := folders[strings.ToLower(m.folder())]
+		if !ok && m.WorkshopID != "" {
+			folder, ok = published[m.WorkshopID]
+		}
+		if ok {
+			c.FolderExists = true
+			c.Folder = folder
+			if c.Keys, err = s.modKeys(svc, folder); err != nil {
+				return checks, err
+			}
+			for _, k := range c.Keys {
+				if !installed[strings.ToLower(k)] {
+					c.MissingKeys = append(c.MissingKeys, k)
+				}
+			}
+		}
+		checks = append(checks, c)
+	}
+
+	return checks, nil
+}
+
+// publishedIDs returns the mod folders among entries by the wanted workshop
+// IDs in their meta.cpp, skipping claimed folders. Folders without a
+// readable meta.cpp are left out.
+func (s *ModsService) publishedIDs(svc Service, entries []File, claimed, wanted map[string]bool) map[string]string {
+	ids := make(map[string]string)
+	for _, e := range entries {
+		if len(ids) == len(wanted) {
+			break
+		}
+		if e.Type != FileTypeDir || !strings.HasPrefix(e.Name, "@") || claimed[e.Path] {
+			continue
+		}
+		body, _, err := s.client.FileServerService.fetch(svc, path.Join(e.Path, "meta.cpp"))
+		if err != nil {
+			continue
+		}
+		meta, err := io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			continue
+		}
+		if m := publishedIDRe.FindSubmatch(meta); m != nil && wanted[string(m[1])] {
+			ids[string(m[1])] = e.Path
+		}
+	}
+	return ids
+}
+
+// modKeys returns the names of the .bikey files in the keys folder of a mod.
+func (s *ModsService) modKeys(svc Service, folder string) ([]string, error) {
+	entries, _, err := s.client.FileServerService.List(svc, FileServerListOptions{Dir: folder, Type: FileTypeDir})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if name := strings.ToLower(e.Name); name == "keys" || name == "key" {
+			return s.bikeys(svc, e.Path)
+		}
+	}
+	return nil, nil
+}
+
+// bikeys returns the names of the .bikey files in dir, or nothing when dir
+// is blank.
+func (s *ModsService) bikeys(svc Service, dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, _, err := s.client.FileServerService.List(svc, FileServerListOptions{
+		Dir:    dir,
+		Type:   FileTypeFile,
+		Names:  []string{"*.bikey", "*.BIKEY"},
+		SortBy: FileSortName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Name
+	}
+	return keys, nil
+}
@@ -0,0 +1,152 @@
+package nitrado
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModList tests the ModList list operations.
+func TestModList(t *testing.T) {
+	const value = "1559212036; @CF;\r\nCommunity-Online-Tools,1559212036;;"
+	l := newModList(1, value)
+	mods := l.Mods()
+	require.Len(t, mods, 3)
+	assert.Equal(t, "1559212036", mods[0].WorkshopID)
+	assert.Equal(t, "@CF", mods[1].Folder)
+	assert.Equal(t, "@Community-Online-Tools", mods[2].Folder)
+	assert.Equal(t, "Community-Online-Tools", mods[2].String())
+	assert.Equal(t, 3, l.Len())
+	assert.True(t, l.Contains("@cf"))
+	assert.True(t, l.Contains("CF"))
+	assert.Equal(t, value, l.String())
+
+	assert.False(t, l.Add("CF"))
+	assert.False(t, l.Add(" "))
+	require.Nil(t, l.Move("@CF", 1))
+	assert.Equal(t, value, l.String())
+
+	assert.True(t, l.Add("@Trader"))
+	assert.False(t, l.Add("Trader"))
+	assert.True(t, l.Remove("1559212036"))
+	assert.False(t, l.Remove("1559212036"))
+	assert.Equal(t, "@CF;Community-Online-Tools;@Trader", l.String())
+
+	require.Nil(t, l.Move("@Trader", 0))
+	assert.Equal(t, "@Trader;@CF;Community-Online-Tools", l.String())
+	require.Nil(t, l.Move("@Trader", 2))
+	assert.Equal(t, "@CF;Community-Online-Tools;@Trader", l.String())
+	assert.Error(t, l.Move("@Missing", 0))
+	assert.Error(t, l.Move("@CF", 3))
+
+	// The separator of the list is kept.
+	l = newModList(1, "CF\r\n@Trader\r\n")
+	assert.True(t, l.Add("1559212036"))
+	assert.Equal(t, "CF\r\n@Trader\r\n1559212036", l.String())
+}
+
+// TestModsService_Save tests the ModsService List() and Save() methods.
+func TestModsService_Save(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &settingsServer{settings: Settings{"general": {"additionalMods": "CF,@Trader"}}}
+	server.register(t, mux)
+
+	mods, _, err := client.Mods.List(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, 2, mods.Len())
+	require.Nil(t, client.Mods.Save(mods))
+	assert.Equal(t, "CF,@Trader", server.settings["general"]["additionalMods"])
+	mods.Add("@Community-Online-Tools")
+	require.Nil(t, mods.Move("@Community-Online-Tools", 1))
+	require.Nil(t, client.Mods.Save(mods))
+	assert.Equal(t, "CF,@Community-Online-Tools,@Trader", server.settings["general"]["additionalMods"])
+
+	server.settings.Set("general", "additionalMods", "@CF")
+	mods.Remove("@Trader")
+	assert.ErrorIs(t, client.Mods.Save(mods), ErrModListChanged)
+	assert.Equal(t, "@CF", server.settings["general"]["additionalMods"])
+}
+
+// TestModsService_Verify tests the ModsService Verify() method.
+func TestModsService_Verify(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+
+	const dir = "/games/ni1_1/noftp/dayzxb"
+	dirs := map[string][]string{
+		dir:                                   {"@CF/", "@Trader/", "@1559212036/", "@Community-Online-Tools/", "@Unused/", "keys/", "serverDZ.cfg"},
+		dir + "/keys":                         {"dayz.bikey", "CF.bikey"},
+		dir + "/@CF":                          {"addons/", "Keys/"},
+		dir + "/@CF/Keys":                     {"CF.bikey"},
+		dir + "/@Trader":                      {"addons/", "keys/"},
+		dir + "/@Trader/keys":                 {"Trader.bikey", "readme.txt"},
+		dir + "/@1559212036":                  {"addons/"},
+		dir + "/@Community-Online-Tools":      {"addons/", "keys/", "meta.cpp"},
+		dir + "/@Community-Online-Tools/keys": {"COT.bikey"},
+	}
+	contents := map[string]string{
+		dir + "/@Community-Online-Tools/meta.cpp": "protocol = 1;\npublishedid = 1564026768;\ntimestamp = 5249258296935426816;\n",
+		dir + "/@Unused/meta.cpp":                 "protocol = 1;\npublishedid = 1234;\n",
+	}
+	var downloads []string
+	mux.HandleFunc("/services/7654321/gameservers/file_server/list", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		d := r.URL.Query().Get("dir")
+		var resp FileListResp
+		resp.Status = "success"
+		for _, name := range dirs[d] {
+			f := File{Name: name, Path: path.Join(d, name), Type: FileTypeFile}
+			if name[len(name)-1] == '/' {
+				f.Name = name[:len(name)-1]
+				f.Path = path.Join(d, f.Name)
+				f.Type = FileTypeDir
+			}
+			resp.Data.Entries = append(resp.Data.Entries, f)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/file_server/download", func(w http.ResponseWriter, r *http.Request) {
+		downloads = append(downloads, r.URL.Query().Get("file"))
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"token":{"url":"%s%s/download?file=%s"}}}`, serverURL, baseURLPath, r.URL.Query().Get("file"))
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		content, ok := contents[r.URL.Query().Get("file")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, content)
+	})
+
+	mods := newModList(7654321, "@CF;@Trader;1559212036;@Missing;1564026768")
+	checks, err := client.Mods.Verify(Service{ID: 7654321}, mods, dir)
+	require.Nil(t, err)
+	require.Len(t, checks, 5)
+
+	assert.True(t, checks[0].OK())
+	assert.Equal(t, []string{"CF.bikey"}, checks[0].Keys)
+
+	assert.False(t, checks[1].OK())
+	assert.Equal(t, []string{"Trader.bikey"}, checks[1].MissingKeys)
+
+	assert.True(t, checks[2].OK())
+	assert.Equal(t, dir+"/@1559212036", checks[2].Folder)
+	assert.Empty(t, checks[2].Keys)
+
+	assert.False(t, checks[3].OK())
+	assert.False(t, checks[3].FolderExists)
+	assert.Equal(t, dir+"/@Missing", checks[3].Folder)
+
+	// The workshop mod is found by the publishedid in its meta.cpp.
+	assert.True(t, checks[4].FolderExists)
+	assert.Equal(t, dir+"/@Community-Online-Tools", checks[4].Folder)
+	assert.Equal(t, []string{"COT.bikey"}, checks[4].MissingKeys)
+	// Folders of other mods are not read, nor any after the last workshop mod is found.
+	assert.Equal(t, []string{dir + "/@Community-Online-Tools/meta.cpp"}, downloads)
+}
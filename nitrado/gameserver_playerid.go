@@ -0,0 +1,72 @@
+package nitrado
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// PlayerIDType is the kind of identifier in Player.ID.
+type PlayerIDType string
+
+// The types of player identifier returned by Nitrado.
+const (
+	PlayerIDInternal  PlayerIDType = "internal" // An identifier assigned by the game server.
+	PlayerIDSteam     PlayerIDType = "steamid"  // A Steam64 ID, e.g. "76561197960287930".
+	PlayerIDXbox      PlayerIDType = "xuid"     // An Xbox user ID.
+	PlayerIDPSN       PlayerIDType = "psnid"    // A PlayStation Network online ID.
+	PlayerIDMinecraft PlayerIDType = "uuid"     // A Minecraft account UUID.
+)
+
+var (
+	steamIDRe     = regexp.MustCompile(`^7656119\d{10}$`)
+	xboxIDRe      = regexp.MustCompile(`^(\d{15,16}|[0-9A-Fa-f]{16})$`)
+	psnIDRe       = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{2,15}$`)
+	minecraftIDRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+)
+
+// ValidID reports whether id is well formed for the identifier type. Unknown
+// types accept any non-blank id.
+func (t PlayerIDType) ValidID(id string) bool {
+	switch t {
+	case PlayerIDSteam:
+		return steamIDRe.MatchString(id)
+	case PlayerIDXbox:
+		return xboxIDRe.MatchString(id)
+	case PlayerIDPSN:
+		return psnIDRe.MatchString(id)
+	case PlayerIDMinecraft:
+		return minecraftIDRe.MatchString(id)
+	default:
+		return id != ""
+	}
+}
+
+// ValidID reports whether the ID of the player is well formed for its IDType.
+func (p Player) ValidID() bool {
+	return p.IDType.ValidID(p.ID)
+}
+
+// ProfileURL returns a link to the public profile of the player on their
+// platform, or an empty string when there is no such profile. Xbox profiles
+// are linked by gamertag, so they need the player name.
+func (p Player) ProfileURL() string {
+	if !p.ValidID() {
+		return ""
+	}
+
+	switch p.IDType {
+	case PlayerIDSteam:
+		return "https://steamcommunity.com/profiles/" + p.ID
+	case PlayerIDXbox:
+		if p.Name == "" {
+			return ""
+		}
+		return "https://account.xbox.com/profile?gamertag=" + url.QueryEscape(p.Name)
+	case PlayerIDPSN:
+		return "https://my.playstation.com/profile/" + p.ID
+	case PlayerIDMinecraft:
+		return "https://namemc.com/profile/" + p.ID
+	default:
+		return ""
+	}
+}
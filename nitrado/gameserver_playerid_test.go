@@ -0,0 +1,70 @@
+package nitrado
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlayer_ProfileURL tests the Player ValidID() and ProfileURL() methods.
+func TestPlayer_ProfileURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		player    Player
+		wantValid bool
+		want      string
+	}{
+		{
+			name:      "Steam",
+			player:    Player{ID: "76561197960287930", IDType: PlayerIDSteam},
+			wantValid: true,
+			want:      "https://steamcommunity.com/profiles/76561197960287930",
+		},
+		{
+			name:   "Invalid Steam",
+			player: Player{ID: "1234", IDType: PlayerIDSteam},
+		},
+		{
+			name:      "Xbox",
+			player:    Player{Name: "Gamer Tag 1", ID: "2535405290000000", IDType: PlayerIDXbox},
+			wantValid: true,
+			want:      "https://account.xbox.com/profile?gamertag=Gamer+Tag+1",
+		},
+		{
+			name:      "Xbox without a name",
+			player:    Player{ID: "2535405290000000", IDType: PlayerIDXbox},
+			wantValid: true,
+		},
+		{
+			name:      "PSN",
+			player:    Player{ID: "Player_25", IDType: PlayerIDPSN},
+			wantValid: true,
+			want:      "https://my.playstation.com/profile/Player_25",
+		},
+		{
+			name:   "Invalid PSN",
+			player: Player{ID: "25", IDType: PlayerIDPSN},
+		},
+		{
+			name:      "Minecraft",
+			player:    Player{ID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", IDType: PlayerIDMinecraft},
+			wantValid: true,
+			want:      "https://namemc.com/profile/069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		},
+		{
+			name:      "Internal",
+			player:    Player{ID: "abcdefg12345678", IDType: PlayerIDInternal},
+			wantValid: true,
+		},
+		{
+			name:   "Blank",
+			player: Player{IDType: PlayerIDInternal},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantValid, tt.player.ValidID())
+			assert.Equal(t, tt.want, tt.player.ProfileURL())
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package nitrado
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
 )
 
 // Generated structs from https://mholt.github.io/json-to-go/
@@ -15,17 +19,90 @@ type PlayerListService apiService
 
 // Player contains the details of a player
 type Player struct {
-	Name    string   `json:"name,omitempty"`
-	ID      string   `json:"id,omitempty"`
-	IDType  string   `json:"id_type,omitempty"`
-	Online  string   `json:"online,omitempty"`
-	Actions []string `json:"actions,omitempty"`
+	Name    string       `json:"name,omitempty"`
+	ID      string       `json:"id,omitempty"`
+	IDType  PlayerIDType `json:"id_type,omitempty"`
+	Online  bool         `json:"online,omitempty"`
+	Actions []string     `json:"actions,omitempty"`
+}
+
+// UnmarshalJSON decodes a player, accepting the online flag as either a
+// boolean, a number or a string such as "true" or "1".
+func (p *Player) UnmarshalJSON(data []byte) error {
+	type player Player
+	aux := struct {
+		*player
+		Online interface{} `json:"online,omitempty"`
+	}{player: (*player)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	switch v := aux.Online.(type) {
+	case bool:
+		p.Online = v
+	case float64:
+		p.Online = v != 0
+	case string:
+		online, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil && strings.TrimSpace(v) != "" {
+			return fmt.Errorf("invalid online value %q for player %q", v, p.ID)
+		}
+		p.Online = online
+	case nil:
+		p.Online = false
+	default:
+		return fmt.Errorf("invalid online value %v for player %q", v, p.ID)
+	}
+
+	return nil
+}
+
+// PlayerAction is a player management action which Nitrado may allow for a
+// player, as listed in Player.Actions.
+type PlayerAction string
+
+// The player management actions supported by the PlayerListService.
+const (
+	PlayerActionKick        PlayerAction = "kick"
+	PlayerActionBan         PlayerAction = "ban"
+	PlayerActionUnban       PlayerAction = "unban"
+	PlayerActionWhitelist   PlayerAction = "whitelist"
+	PlayerActionUnwhitelist PlayerAction = "unwhitelist"
+	PlayerActionOp          PlayerAction = "op"
+	PlayerActionDeop        PlayerAction = "deop"
+)
+
+// ErrActionNotAllowed is returned when an action is requested for a player
+// whose Actions do not include it.
+var ErrActionNotAllowed = errors.New("player action not allowed")
+
+// Allows reports whether Nitrado allows the action for the player.
+func (p Player) Allows(action PlayerAction) bool {
+	for _, a := range p.Actions {
+		if a == string(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlayerActionOptions controls the query string settings that a player action request can take.
+type PlayerActionOptions struct {
+	Identifier string `url:"identifier,omitempty"`
+}
+
+// PlayerActionResp contains the response object from a player action.
+type PlayerActionResp struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 // PlayerListResp contains a listing of the players for a gameserver
 type PlayerListResp struct {
-	Status string `json:"status,omitempty"`
-	Data   struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Players []Player `json:"players,omitempty"`
 	} `json:"data,omitempty"`
 }
@@ -46,6 +123,9 @@ func (s *PlayerListService) List(svc Service) ([]Player, *http.Response, error)
 	if err != nil {
 		return nil, resp, err
 	}
+	if playerListResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", playerListResp.Status, playerListResp.Message)
+	}
 
 	// Sort the files by Modified date
 	sort.Slice(playerListResp.Data.Players, func(i, j int) bool {
@@ -54,3 +134,85 @@ func (s *PlayerListService) List(svc Service) ([]Player, *http.Response, error)
 
 	return playerListResp.Data.Players, resp, nil
 }
+
+// Kick a player from a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-Kick
+func (s *PlayerListService) Kick(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionKick, "POST", "players/kick")
+}
+
+// Ban a player from a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-BanAdd
+func (s *PlayerListService) Ban(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionBan, "PUT", "banlist")
+}
+
+// Unban a player on a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-BanRemove
+func (s *PlayerListService) Unban(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionUnban, "DELETE", "banlist")
+}
+
+// Whitelist adds a player to the whitelist of a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-WhitelistAdd
+func (s *PlayerListService) Whitelist(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionWhitelist, "PUT", "whitelist")
+}
+
+// Unwhitelist removes a player from the whitelist of a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-WhitelistRemove
+func (s *PlayerListService) Unwhitelist(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionUnwhitelist, "DELETE", "whitelist")
+}
+
+// Op adds a player to the admin list of a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-AdminAdd
+func (s *PlayerListService) Op(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionOp, "PUT", "adminlist")
+}
+
+// Deop removes a player from the admin list of a GameServer.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Player_Management-AdminRemove
+func (s *PlayerListService) Deop(svc Service, player Player) (*http.Response, error) {
+	return s.action(svc, player, PlayerActionDeop, "DELETE", "adminlist")
+}
+
+// action sends a player management request, after checking that Nitrado
+// allows the action for the player.
+func (s *PlayerListService) action(svc Service, player Player, action PlayerAction, method, endpoint string) (*http.Response, error) {
+	if player.ID == "" {
+		return nil, fmt.Errorf("player id must not be blank")
+	}
+	if !player.Allows(action) {
+		return nil, fmt.Errorf("%w: %q for player %q, allowed actions are %q", ErrActionNotAllowed, action, player.ID, player.Actions)
+	}
+
+	u := fmt.Sprintf("services/%v/gameservers/games/%v", svc.ID, endpoint)
+	u, err := addOptions(u, PlayerActionOptions{Identifier: player.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var actionResp *PlayerActionResp
+	resp, err := s.client.Do(req, &actionResp)
+	if err != nil {
+		return resp, err
+	}
+	if actionResp.Status != "success" {
+		return resp, fmt.Errorf("status %q (%q)", actionResp.Status, actionResp.Message)
+	}
+
+	return resp, nil
+}
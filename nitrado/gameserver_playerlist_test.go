@@ -1,10 +1,14 @@
 package nitrado
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // JSON minified using https://codebeautify.org/jsonminifier
@@ -40,28 +44,28 @@ func TestPlayerListService_List(t *testing.T) {
 					Name:    "",
 					ID:      "abcdefg12345678",
 					IDType:  "internal",
-					Online:  "true",
+					Online:  true,
 					Actions: []string{"kick"},
 				},
 				{
 					Name:    "",
 					ID:      "bcdefg123456790",
 					IDType:  "internal",
-					Online:  "true",
+					Online:  true,
 					Actions: []string{"kick"},
 				},
 				{
 					Name:    "Player25",
 					ID:      "hijklmnop0987654321",
 					IDType:  "internal",
-					Online:  "true",
+					Online:  true,
 					Actions: []string{"kick", "promotion_level_0", "promotion_level_2", "promotion_level_3"},
 				},
 				{
 					Name:    "Player26",
 					ID:      "ijklmnop0987654322",
 					IDType:  "internal",
-					Online:  "true",
+					Online:  true,
 					Actions: []string{"kick", "promotion_level_0", "promotion_level_2", "promotion_level_3"},
 				},
 			},
@@ -81,3 +85,101 @@ func TestPlayerListService_List(t *testing.T) {
 		})
 	}
 }
+
+// TestPlayerListService_Actions tests the PlayerListService player management methods.
+func TestPlayerListService_Actions(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []string
+	handle := func(endpoint string) {
+		mux.HandleFunc("/services/7654321/gameservers/games/"+endpoint, func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, fmt.Sprintf("%s %s %s", r.Method, endpoint, r.URL.Query().Get("identifier")))
+			_, _ = fmt.Fprint(w, `{"status":"success","message":"Done."}`)
+		})
+	}
+	handle("players/kick")
+	handle("banlist")
+	handle("whitelist")
+	handle("adminlist")
+	mux.HandleFunc("/services/999/gameservers/games/players/kick", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"Player is not online."}`)
+	})
+
+	s := client.PlayerListService
+	svc := Service{ID: 7654321}
+	all := Player{ID: "abc123", Actions: []string{"kick", "ban", "unban", "whitelist", "unwhitelist", "op", "deop"}}
+	kickOnly := Player{ID: "def456", Actions: []string{"kick"}}
+
+	tests := []struct {
+		name    string
+		call    func(Service, Player) (*http.Response, error)
+		svc     Service
+		player  Player
+		want    string
+		wantErr error
+	}{
+		{name: "Kick", call: s.Kick, svc: svc, player: all, want: "POST players/kick abc123"},
+		{name: "Ban", call: s.Ban, svc: svc, player: all, want: "PUT banlist abc123"},
+		{name: "Unban", call: s.Unban, svc: svc, player: all, want: "DELETE banlist abc123"},
+		{name: "Whitelist", call: s.Whitelist, svc: svc, player: all, want: "PUT whitelist abc123"},
+		{name: "Unwhitelist", call: s.Unwhitelist, svc: svc, player: all, want: "DELETE whitelist abc123"},
+		{name: "Op", call: s.Op, svc: svc, player: all, want: "PUT adminlist abc123"},
+		{name: "Deop", call: s.Deop, svc: svc, player: all, want: "DELETE adminlist abc123"},
+		{name: "Not allowed", call: s.Ban, svc: svc, player: kickOnly, wantErr: ErrActionNotAllowed},
+		{name: "Failure", call: s.Kick, svc: Service{ID: 999}, player: kickOnly},
+		{name: "Missing ID", call: s.Kick, svc: svc, player: Player{Actions: []string{"kick"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			_, err := tt.call(tt.svc, tt.player)
+			if tt.want == "" {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				assert.Empty(t, got)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, []string{tt.want}, got)
+		})
+	}
+}
+
+// TestPlayer_UnmarshalJSON tests decoding the mixed representations of the online flag.
+func TestPlayer_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    bool
+		wantErr bool
+	}{
+		{json: `{"id":"a","online":true}`, want: true},
+		{json: `{"id":"a","online":false}`, want: false},
+		{json: `{"id":"a","online":"true"}`, want: true},
+		{json: `{"id":"a","online":"false"}`, want: false},
+		{json: `{"id":"a","online":"1"}`, want: true},
+		{json: `{"id":"a","online":"0"}`, want: false},
+		{json: `{"id":"a","online":""}`, want: false},
+		{json: `{"id":"a","online":1}`, want: true},
+		{json: `{"id":"a","online":0}`, want: false},
+		{json: `{"id":"a","online":null}`, want: false},
+		{json: `{"id":"a"}`, want: false},
+		{json: `{"id":"a","online":"maybe"}`, wantErr: true},
+		{json: `{"id":"a","online":[]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var p Player
+			err := json.Unmarshal([]byte(tt.json), &p)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, "a", p.ID)
+			assert.Equal(t, tt.want, p.Online)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package nitrado
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval is used when PlayerWatcher.Interval is not set.
+const defaultWatchInterval = 30 * time.Second
+
+// PlayerEventType identifies the kind of a PlayerEvent.
+type PlayerEventType string
+
+// The events sent by a PlayerWatcher.
+const (
+	PlayerJoined  PlayerEventType = "joined"
+	PlayerLeft    PlayerEventType = "left"
+	PlayerRenamed PlayerEventType = "renamed"
+)
+
+// PlayerEvent is a change in the players online on a GameServer.
+type PlayerEvent struct {
+	Type         PlayerEventType
+	Time         time.Time
+	Player       Player
+	PreviousName string        // The name before a rename.
+	Session      time.Duration // The length of the session that ended, for PlayerLeft events.
+}
+
+// playerSession is a player who is currently online.
+type playerSession struct {
+	player Player
+	since  time.Time
+}
+
+// PlayerWatcher polls the player list of a GameServer and sends an event
+// whenever a player joins, leaves or is renamed. Players are matched by ID.
+// It also keeps the total time each player has been seen online.
+//
+// The player list is only compared while the GameServer is started, as it is
+// empty while the server is stopped or restarting. Players who left in the
+// meantime are reported once the server is started again.
+type PlayerWatcher struct {
+	client *Client
+	svc    Service
+
+	Interval time.Duration // How often to poll the player list, defaults to 30 seconds.
+
+	// IgnoreInitial suppresses the joined events for players who are already
+	// online when the watcher starts.
+	IgnoreInitial bool
+
+	// OnError is called when polling the player list fails. The watcher keeps
+	// the previous player list and tries again on the next poll, so a failed
+	// poll never causes players to be reported as having left.
+	OnError func(error)
+
+	mu      sync.Mutex
+	started bool
+	online  map[string]playerSession
+	totals  map[string]time.Duration
+}
+
+// NewPlayerWatcher returns a PlayerWatcher for the GameServer of the given service.
+func NewPlayerWatcher(c *Client, svc Service) *PlayerWatcher {
+	return &PlayerWatcher{
+		client: c,
+		svc:    svc,
+		online: make(map[string]playerSession),
+		totals: make(map[string]time.Duration),
+	}
+}
+
+// Run polls the player list until ctx is cancelled, sending events to
+// events. Run does not close events.
+func (w *PlayerWatcher) Run(ctx context.Context, events chan<- PlayerEvent) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx, events); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if w.OnError != nil {
+				w.OnError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the player list once and sends an event for every change
+// since the previous poll. When the GameServer is not started, no events are
+// sent and the previous player list is kept. When the game server or player
+// list cannot be fetched, or the API returns an error, the error is returned
+// and no events are sent.
+func (w *PlayerWatcher) Poll(ctx context.Context, events chan<- PlayerEvent) error {
+	gs, _, err := w.client.GameServers.Get(w.svc.ID)
+	if err != nil {
+		return fmt.Errorf("getting game server: %w", err)
+	}
+	if gs.Status != "started" {
+		return nil
+	}
+	players, _, err := w.client.PlayerListService.List(w.svc)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range w.diff(players, time.Now()) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events <- e:
+		}
+	}
+	return nil
+}
+
+// diff updates the online players from a new player list, returning the
+// resulting events.
+func (w *PlayerWatcher) diff(players []Player, now time.Time) []PlayerEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	initial := !w.started
+	w.started = true
+
+	var events []PlayerEvent
+	current := make(map[string]bool)
+	for _, p := range players {
+		if !p.Online || p.ID == "" {
+			continue
+		}
+		current[p.ID] = true
+
+		s, ok := w.online[p.ID]
+		switch {
+		case !ok:
+			w.online[p.ID] = playerSession{player: p, since: now}
+			if !initial || !w.IgnoreInitial {
+				events = append(events, PlayerEvent{Type: PlayerJoined, Time: now, Player: p})
+			}
+		case s.player.Name != p.Name:
+			events = append(events, PlayerEvent{Type: PlayerRenamed, Time: now, Player: p, PreviousName: s.player.Name})
+			s.player = p
+			w.online[p.ID] = s
+		}
+	}
+
+	var left []PlayerEvent
+	for id, s := range w.online {
+		if current[id] {
+			continue
+		}
+		session := now.Sub(s.since)
+		w.totals[id] += session
+		delete(w.online, id)
+		left = append(left, PlayerEvent{Type: PlayerLeft, Time: now, Player: s.player, Session: session})
+	}
+	sort.Slice(left, func(i, j int) bool {
+		return left[i].Player.Name < left[j].Player.Name
+	})
+
+	return append(events, left...)
+}
+
+// Online returns the players who were online at the last poll, sorted by name.
+func (w *PlayerWatcher) Online() []Player {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	players := make([]Player, 0, len(w.online))
+	for _, s := range w.online {
+		players = append(players, s.player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+	return players
+}
+
+// SessionTimes returns the total time each player has been online, keyed by
+// player ID. The current session of online players is included up to now.
+func (w *PlayerWatcher) SessionTimes() map[string]time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	totals := make(map[string]time.Duration, len(w.totals)+len(w.online))
+	for id, d := range w.totals {
+		totals[id] = d
+	}
+	for id, s := range w.online {
+		totals[id] += now.Sub(s.since)
+	}
+	return totals
+}
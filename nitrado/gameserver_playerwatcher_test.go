@@ -0,0 +1,229 @@
+package nitrado
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playerListServer serves a game server and its player list, which can be
+// changed by the test.
+type playerListServer struct {
+	mu       sync.Mutex
+	gsStatus string // The status of the game server, "started" if empty.
+	players  []Player
+	broken   bool
+	status   int // The HTTP status of an API error to respond with, if set.
+}
+
+func (s *playerListServer) set(players ...Player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.players = players
+	s.broken = false
+	s.status = 0
+}
+
+func (s *playerListServer) setStatus(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gsStatus = status
+}
+
+func (s *playerListServer) breakNext() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.broken = true
+}
+
+func (s *playerListServer) fail(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *playerListServer) register(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/services/7654321/gameservers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		status := s.gsStatus
+		if status == "" {
+			status = "started"
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"gameserver":{"status":%q}}}`, status)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/games/players", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.broken {
+			_, _ = fmt.Fprint(w, `{"status":"success","data":`)
+			return
+		}
+		if s.status != 0 {
+			w.WriteHeader(s.status)
+			_, _ = fmt.Fprint(w, `{"status":"error","message":"Service unavailable"}`)
+			return
+		}
+		var resp PlayerListResp
+		resp.Status = "success"
+		resp.Data.Players = s.players
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// poll runs a single poll of the watcher and returns the events it sent.
+func poll(t *testing.T, w *PlayerWatcher) ([]PlayerEvent, error) {
+	t.Helper()
+	events := make(chan PlayerEvent, 10)
+	err := w.Poll(context.Background(), events)
+	close(events)
+	var got []PlayerEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	return got, err
+}
+
+// eventSummary renders events as "type:name" strings for easy comparison.
+func eventSummary(events []PlayerEvent) []string {
+	var got []string
+	for _, e := range events {
+		got = append(got, fmt.Sprintf("%s:%s", e.Type, e.Player.Name))
+	}
+	return got
+}
+
+// TestPlayerWatcher_Poll tests the PlayerWatcher Poll() method.
+func TestPlayerWatcher_Poll(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &playerListServer{}
+	server.register(t, mux)
+
+	alice := Player{ID: "a", Name: "Alice", Online: true}
+	bob := Player{ID: "b", Name: "Bob", Online: true}
+	carol := Player{ID: "c", Name: "Carol", Online: false}
+	w := NewPlayerWatcher(client, Service{ID: 7654321})
+
+	server.set(alice, bob, carol)
+	events, err := poll(t, w)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"joined:Alice", "joined:Bob"}, eventSummary(events))
+
+	// A failed poll does not report anyone as having left.
+	server.breakNext()
+	events, err = poll(t, w)
+	assert.Error(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, []Player{alice, bob}, w.Online())
+
+	renamed := bob
+	renamed.Name = "Robert"
+	server.set(renamed)
+	events, err = poll(t, w)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"renamed:Robert", "left:Alice"}, eventSummary(events))
+	assert.Equal(t, "Bob", events[0].PreviousName)
+	assert.True(t, events[1].Session > 0)
+
+	// An empty list while the server restarts does not report anyone as
+	// having left.
+	server.setStatus("restarting")
+	server.set()
+	events, err = poll(t, w)
+	require.Nil(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, []Player{renamed}, w.Online())
+
+	server.setStatus("started")
+	events, err = poll(t, w)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"left:Robert"}, eventSummary(events))
+	assert.Empty(t, w.Online())
+
+	times := w.SessionTimes()
+	assert.Len(t, times, 2)
+	assert.True(t, times["a"] > 0)
+	assert.True(t, times["b"] > 0)
+}
+
+// TestPlayerWatcher_Poll_apiError tests that an API error response does not report players as having left.
+func TestPlayerWatcher_Poll_apiError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.RetryDelay = 0
+	server := &playerListServer{}
+	server.register(t, mux)
+
+	alice := Player{ID: "a", Name: "Alice", Online: true}
+	w := NewPlayerWatcher(client, Service{ID: 7654321})
+
+	server.set(alice)
+	_, err := poll(t, w)
+	require.Nil(t, err)
+
+	server.fail(http.StatusServiceUnavailable)
+	events, err := poll(t, w)
+	assert.EqualError(t, err, `status "error" ("Service unavailable")`)
+	assert.Empty(t, events)
+	assert.Equal(t, []Player{alice}, w.Online())
+
+	server.set(alice)
+	events, err = poll(t, w)
+	require.Nil(t, err)
+	assert.Empty(t, events)
+}
+
+// TestPlayerWatcher_Run tests the PlayerWatcher Run() method.
+func TestPlayerWatcher_Run(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &playerListServer{}
+	server.register(t, mux)
+	server.set(Player{ID: "a", Name: "Alice", Online: true})
+
+	w := NewPlayerWatcher(client, Service{ID: 7654321})
+	w.Interval = 10 * time.Millisecond
+	w.IgnoreInitial = true
+	var errs []error
+	var mu sync.Mutex
+	w.OnError = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan PlayerEvent)
+	done := make(chan error)
+	go func() { done <- w.Run(ctx, events) }()
+
+	require.Eventually(t, func() bool { return len(w.Online()) == 1 }, 5*time.Second, 5*time.Millisecond)
+	server.breakNext()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) > 0
+	}, 5*time.Second, 5*time.Millisecond)
+	server.set(Player{ID: "a", Name: "Alice", Online: true}, Player{ID: "b", Name: "Bob", Online: true})
+
+	select {
+	case e := <-events:
+		assert.Equal(t, PlayerJoined, e.Type)
+		assert.Equal(t, "Bob", e.Player.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for joined event")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
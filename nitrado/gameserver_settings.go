@@ -1,7 +1,12 @@
 package nitrado
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 )
 
 // Generated structs from https://mholt.github.io/json-to-go/
@@ -21,16 +26,40 @@ type GSSettingsUpdateOptions struct {
 	Category string `url:"category,omitempty"`
 	Key      string `url:"key,omitempty"`
 	Value    string `url:"value"`
+
+	// Schema is used to validate the setting before it is sent. When not
+	// set, the schema of the GameServer is fetched the first time a setting
+	// of the service is updated, and kept by the client.
+	Schema SettingsSchema `url:"-"`
+
+	// SkipValidation sends the setting without validating it.
+	SkipValidation bool `url:"-"`
 }
 
 // Update a setting on a GameServer by service ID.
 //
 // Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Details
 // Requires a settings category as well as a key and value for the setting.
+// Unknown settings and invalid values are rejected with ErrInvalidSetting
+// without sending a request, and the value is sent as it was validated,
+// without surrounding spaces unless it is text. Games without a settings
+// schema are not validated.
 func (s *GSSettingsService) Update(serviceID int, opts GSSettingsUpdateOptions) error {
 	if opts.Category == "" || opts.Key == "" {
 		return fmt.Errorf("category and key must not be blank. category=%q, key=%q", opts.Category, opts.Key)
 	}
+	if !opts.SkipValidation {
+		schema, err := s.validationSchema(serviceID, opts.Schema)
+		if err != nil {
+			return err
+		}
+		if schema != nil {
+			opts.Value = schema.normalize(opts.Category, opts.Key, opts.Value)
+			if err := schema.Validate(opts.Category, opts.Key, opts.Value); err != nil {
+				return err
+			}
+		}
+	}
 	u := fmt.Sprintf("services/%v/gameservers/settings", serviceID)
 	u, err := addOptions(u, opts)
 	if err != nil {
@@ -53,3 +82,244 @@ func (s *GSSettingsService) Update(serviceID int, opts GSSettingsUpdateOptions)
 
 	return nil
 }
+
+// errSettingChanged is returned by updateIfUnchanged when the setting was
+// changed on the server.
+var errSettingChanged = errors.New("setting changed since it was read")
+
+// updateIfUnchanged updates a general setting of a GameServer to value, as
+// long as same reports that its current value on the server still matches
+// original. Otherwise errSettingChanged is returned without updating
+// anything.
+func (s *GSSettingsService) updateIfUnchanged(serviceID int, key, original, value string, same func(a, b string) bool) error {
+	gs, _, err := s.client.GameServers.Get(serviceID)
+	if err != nil {
+		return err
+	}
+	current, _ := gs.Settings.Value("general", key)
+	if !same(current, original) {
+		return errSettingChanged
+	}
+
+	return s.Update(serviceID, GSSettingsUpdateOptions{Category: "general", Key: key, Value: value})
+}
+
+// Settings contains the settings of a GameServer, keyed by category and then
+// by setting key. Every value is kept as the string Nitrado stores.
+type Settings map[string]map[string]string
+
+// Value returns the value of a setting and whether it is set.
+func (s Settings) Value(category, key string) (string, bool) {
+	v, ok := s[category][key]
+	return v, ok
+}
+
+// Set sets the value of a setting, creating the category if needed.
+func (s Settings) Set(category, key, value string) {
+	if s[category] == nil {
+		s[category] = make(map[string]string)
+	}
+	s[category][key] = value
+}
+
+// UnmarshalJSON decodes settings, keeping numbers and booleans as their
+// literal JSON text. A category which is an empty array, as the API returns
+// for an empty category, is left out, and any other category which is not
+// an object is an error.
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	if isEmptyArray(data) {
+		*s = make(Settings)
+		return nil
+	}
+	var categories map[string]json.RawMessage
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return err
+	}
+
+	settings := make(Settings, len(categories))
+	for category, raw := range categories {
+		if isEmptyArray(raw) {
+			continue
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return fmt.Errorf("settings category %q is not an object: %w", category, err)
+		}
+		settings[category] = make(map[string]string, len(values))
+		for key, v := range values {
+			v = bytes.TrimSpace(v)
+			switch {
+			case len(v) > 0 && v[0] == '"':
+				var str string
+				if err := json.Unmarshal(v, &str); err != nil {
+					return err
+				}
+				settings[category][key] = str
+			case string(v) == "null":
+				settings[category][key] = ""
+			default:
+				var b bytes.Buffer
+				if err := json.Compact(&b, v); err != nil {
+					return err
+				}
+				settings[category][key] = b.String()
+			}
+		}
+	}
+	*s = settings
+
+	return nil
+}
+
+// isEmptyArray reports whether data is the JSON array [].
+func isEmptyArray(data []byte) bool {
+	var a []json.RawMessage
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) && json.Unmarshal(data, &a) == nil && len(a) == 0
+}
+
+// Get the settings of a GameServer by service ID.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Details
+func (s *GSSettingsService) Get(serviceID int) (Settings, *http.Response, error) {
+	u := fmt.Sprintf("services/%v/gameservers", serviceID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var settingsResp *GameServerDetailResp
+	resp, err := s.client.Do(req, &settingsResp)
+	if err != nil {
+		return nil, resp, err
+	}
+	if settingsResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q", settingsResp.Status)
+	}
+
+	settings := settingsResp.Data.GameServer.Settings
+	if settings == nil {
+		settings = make(Settings)
+	}
+	return settings, resp, nil
+}
+
+// SettingChange is a single setting update in a SettingsPlan.
+type SettingChange struct {
+	Category string
+	Key      string
+	Old      string
+	New      string
+	Added    bool  // The setting was not set before.
+	Err      error // Set when the update failed while applying the plan.
+}
+
+// SettingsPlan contains the updates needed to make the settings of a
+// GameServer match the desired settings.
+type SettingsPlan struct {
+	Changes []SettingChange
+}
+
+// String returns the change as "category.key: old -> new", or as
+// "category.key: new" for an added setting.
+func (c SettingChange) String() string {
+	if c.Added {
+		return fmt.Sprintf("%s.%s: %q", c.Category, c.Key, c.New)
+	}
+	return fmt.Sprintf("%s.%s: %q -> %q", c.Category, c.Key, c.Old, c.New)
+}
+
+// String lists the setting changes, one per line sorted by category and
+// key, so that they can be reviewed before the plan is applied.
+func (p *SettingsPlan) String() string {
+	return planString(p.Changes)
+}
+
+// Failed returns the changes which returned an error while applying the plan.
+func (p *SettingsPlan) Failed() []SettingChange {
+	return planFailed(p.Changes, func(c SettingChange) error { return c.Err })
+}
+
+// DiffSettings returns the changes needed to update current to desired,
+// sorted by category and key. Settings missing from desired are left alone.
+func DiffSettings(current, desired Settings) *SettingsPlan {
+	plan := &SettingsPlan{}
+	for category, values := range desired {
+		for key, value := range values {
+			old, ok := current.Value(category, key)
+			if ok && old == value {
+				continue
+			}
+			plan.Changes = append(plan.Changes, SettingChange{Category: category, Key: key, Old: old, New: value, Added: !ok})
+		}
+	}
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		a, b := plan.Changes[i], plan.Changes[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Key < b.Key
+	})
+	return plan
+}
+
+// GSSettingsApplyOptions controls how settings are applied to a GameServer.
+type GSSettingsApplyOptions struct {
+	DryRun bool // Only compute the plan, do not change anything.
+
+	// Schema is used to validate every change before any is applied, like
+	// GSSettingsUpdateOptions.Schema. SkipValidation applies the changes
+	// without validating them.
+	Schema         SettingsSchema
+	SkipValidation bool
+}
+
+// Apply updates the settings of a GameServer to match desired, which only
+// needs to contain the settings to manage. The current settings are read
+// first and only the settings which differ are updated, one request each.
+// Every change is validated like Update does before any is applied. The
+// returned plan records the result of every update.
+func (s *GSSettingsService) Apply(serviceID int, desired Settings, opts GSSettingsApplyOptions) (*SettingsPlan, error) {
+	var schema SettingsSchema
+	if !opts.SkipValidation {
+		var err error
+		if schema, err = s.validationSchema(serviceID, opts.Schema); err != nil {
+			return nil, err
+		}
+		normalized := make(Settings)
+		for category, values := range desired {
+			for key, value := range values {
+				normalized.Set(category, key, schema.normalize(category, key, value))
+			}
+		}
+		desired = normalized
+	}
+	current, _, err := s.Get(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := DiffSettings(current, desired)
+	if schema != nil {
+		for i := range plan.Changes {
+			c := &plan.Changes[i]
+			c.Err = schema.Validate(c.Category, c.Key, c.New)
+		}
+		if failed := plan.Failed(); len(failed) > 0 {
+			return plan, fmt.Errorf("%d of %d settings are invalid, first error: %w", len(failed), len(plan.Changes), failed[0].Err)
+		}
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	for i := range plan.Changes {
+		c := &plan.Changes[i]
+		c.Err = s.Update(serviceID, GSSettingsUpdateOptions{Category: c.Category, Key: c.Key, Value: c.New, Schema: schema, SkipValidation: schema == nil})
+	}
+
+	if failed := plan.Failed(); len(failed) > 0 {
+		return plan, fmt.Errorf("%d of %d setting updates failed, first error: %w", len(failed), len(plan.Changes), failed[0].Err)
+	}
+
+	return plan, nil
+}
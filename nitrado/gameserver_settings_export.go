@@ -0,0 +1,142 @@
+package nitrado
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsDocumentVersion is the version of the SettingsDocument format
+// written by Export.
+const SettingsDocumentVersion = 1
+
+// SettingsFormat is a serialisation format for a SettingsDocument.
+type SettingsFormat string
+
+// The formats a SettingsDocument can be written in.
+const (
+	SettingsJSON SettingsFormat = "json"
+	SettingsYAML SettingsFormat = "yaml"
+)
+
+// SettingsDocument is a snapshot of the settings of a GameServer, which can
+// be stored and imported onto the same or another GameServer.
+type SettingsDocument struct {
+	Version   int       `json:"version" yaml:"version"`
+	Game      string    `json:"game,omitempty" yaml:"game,omitempty"`
+	ServiceID int       `json:"service_id,omitempty" yaml:"service_id,omitempty"`
+	Exported  time.Time `json:"exported,omitempty" yaml:"exported,omitempty"`
+	Settings  Settings  `json:"settings" yaml:"settings"`
+}
+
+// Marshal serialises the document in the given format.
+func (d *SettingsDocument) Marshal(format SettingsFormat) ([]byte, error) {
+	switch format {
+	case SettingsJSON:
+		return json.MarshalIndent(d, "", "  ")
+	case SettingsYAML:
+		return yaml.Marshal(d)
+	default:
+		return nil, fmt.Errorf("unknown settings format %q", format)
+	}
+}
+
+// ParseSettingsDocument parses a document written by Marshal in either format.
+func ParseSettingsDocument(data []byte) (*SettingsDocument, error) {
+	// YAML is a superset of JSON, so both formats are read by the YAML decoder.
+	var doc SettingsDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Version < 1 || doc.Version > SettingsDocumentVersion {
+		return nil, fmt.Errorf("unsupported settings document version %d", doc.Version)
+	}
+	if doc.Settings == nil {
+		doc.Settings = make(Settings)
+	}
+	return &doc, nil
+}
+
+// Export returns a snapshot of all of the settings of a GameServer by service ID.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Details
+func (s *GSSettingsService) Export(serviceID int) (*SettingsDocument, *http.Response, error) {
+	gs, resp, err := s.client.GameServers.Get(serviceID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	settings := gs.Settings
+	if settings == nil {
+		settings = make(Settings)
+	}
+	return &SettingsDocument{
+		Version:   SettingsDocumentVersion,
+		Game:      gs.Game,
+		ServiceID: serviceID,
+		Exported:  time.Now().UTC().Truncate(time.Second),
+		Settings:  settings,
+	}, resp, nil
+}
+
+// GSSettingsImportOptions controls which settings are imported onto a GameServer.
+type GSSettingsImportOptions struct {
+	// ExcludeSecrets skips every setting whose key contains "password", such
+	// as admin-password and rcon-password.
+	ExcludeSecrets bool
+
+	// Exclude skips settings, given as "category.key" or "category.*".
+	Exclude []string
+
+	// Overrides replace or add settings after exclusions are applied.
+	Overrides Settings
+
+	DryRun bool // Only compute the plan, do not change anything.
+}
+
+// excluded reports whether a setting is skipped by the options.
+func (o GSSettingsImportOptions) excluded(category, key string) bool {
+	if o.ExcludeSecrets && isSecretSetting(key) {
+		return true
+	}
+	for _, e := range o.Exclude {
+		if e == category+"."+key || e == category+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretSetting reports whether a setting key holds a password.
+func isSecretSetting(key string) bool {
+	return strings.Contains(strings.ToLower(key), "password")
+}
+
+// Import applies the settings of a document to a GameServer by service ID.
+// Only settings which differ from the GameServer are updated, using one
+// Update call each, and the returned plan records the result of every key.
+func (s *GSSettingsService) Import(serviceID int, doc *SettingsDocument, opts GSSettingsImportOptions) (*SettingsPlan, error) {
+	if doc.Version < 1 || doc.Version > SettingsDocumentVersion {
+		return nil, fmt.Errorf("unsupported settings document version %d", doc.Version)
+	}
+
+	desired := make(Settings)
+	for category, values := range doc.Settings {
+		for key, value := range values {
+			if !opts.excluded(category, key) {
+				desired.Set(category, key, value)
+			}
+		}
+	}
+	for category, values := range opts.Overrides {
+		for key, value := range values {
+			desired.Set(category, key, value)
+		}
+	}
+
+	return s.Apply(serviceID, desired, GSSettingsApplyOptions{DryRun: opts.DryRun})
+}
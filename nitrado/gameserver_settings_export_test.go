@@ -0,0 +1,122 @@
+package nitrado
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSettingsDocument_Marshal tests that a SettingsDocument survives a round trip in each format.
+func TestSettingsDocument_Marshal(t *testing.T) {
+	doc := &SettingsDocument{
+		Version:  SettingsDocumentVersion,
+		Game:     "dayzxb",
+		Settings: Settings{"config": {"hostname": "My Server", "disableVoN": "0"}, "general": {"bans": "Gamer Tag 1\r\nGamer Tag 2"}},
+	}
+
+	for _, format := range []SettingsFormat{SettingsJSON, SettingsYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := doc.Marshal(format)
+			require.Nil(t, err)
+			got, err := ParseSettingsDocument(data)
+			require.Nil(t, err)
+			assert.Equal(t, doc, got)
+		})
+	}
+
+	_, err := doc.Marshal("toml")
+	assert.Error(t, err)
+}
+
+// TestParseSettingsDocument tests the ParseSettingsDocument() function.
+func TestParseSettingsDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Settings
+		wantErr bool
+	}{
+		{
+			name: "YAML scalars",
+			data: "version: 1\nsettings:\n  config:\n    maxPlayers: 60\n    pvp: true\n    motd:\n",
+			want: Settings{"config": {"maxPlayers": "60", "pvp": "true", "motd": ""}},
+		},
+		{
+			name: "JSON",
+			data: `{"version":1,"settings":{"config":{"hostname":"My Server"}}}`,
+			want: Settings{"config": {"hostname": "My Server"}},
+		},
+		{
+			name: "No settings",
+			data: `{"version":1}`,
+			want: Settings{},
+		},
+		{
+			name:    "Missing version",
+			data:    `{"settings":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "Future version",
+			data:    `{"version":2,"settings":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid",
+			data:    `{"version":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSettingsDocument([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, got.Settings)
+		})
+	}
+}
+
+// TestGSSettingsService_ExportImport tests the GSSettingsService Export() and Import() methods.
+func TestGSSettingsService_ExportImport(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &settingsServer{settings: Settings{
+		"config":  {"hostname": "Tuned Server", "password": "join"},
+		"general": {"admin-password": "secret", "rcon-password": "secret", "priority": "Gamer Tag 1"},
+	}}
+	server.register(t, mux)
+
+	doc, _, err := client.GameServersSettings.Export(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, SettingsDocumentVersion, doc.Version)
+	assert.Equal(t, 7654321, doc.ServiceID)
+	assert.False(t, doc.Exported.IsZero())
+	assert.Equal(t, server.settings, doc.Settings)
+
+	// Import onto a fresh server, reusing the same test server.
+	server.settings = Settings{"config": {"hostname": "New Server", "password": ""}, "general": {"admin-password": "other"}}
+	plan, err := client.GameServersSettings.Import(7654321, doc, GSSettingsImportOptions{
+		ExcludeSecrets: true,
+		Exclude:        []string{"general.priority"},
+		Overrides:      Settings{"config": {"hostname": "Clone"}},
+	})
+	require.Nil(t, err)
+	assert.Len(t, plan.Changes, 1)
+	assert.Equal(t, []string{"config.hostname"}, server.updates)
+	assert.Equal(t, Settings{
+		"config":  {"hostname": "Clone", "password": ""},
+		"general": {"admin-password": "other"},
+	}, server.settings)
+
+	plan, err = client.GameServersSettings.Import(7654321, doc, GSSettingsImportOptions{Exclude: []string{"config.*"}, DryRun: true})
+	require.Nil(t, err)
+	assert.Equal(t, "general.admin-password: \"other\" -> \"secret\"\ngeneral.priority: \"Gamer Tag 1\"\ngeneral.rcon-password: \"secret\"\n", plan.String())
+
+	_, err = client.GameServersSettings.Import(7654321, &SettingsDocument{}, GSSettingsImportOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package nitrado
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SettingType is the type of value a setting accepts.
+type SettingType string
+
+// The types of setting described by a SettingsSchema.
+const (
+	SettingText   SettingType = "text"
+	SettingBool   SettingType = "bool"
+	SettingInt    SettingType = "int"
+	SettingFloat  SettingType = "float"
+	SettingSelect SettingType = "select" // One of SettingDefinition.Values.
+)
+
+// ErrInvalidSetting is returned when a setting is rejected by a SettingsSchema.
+var ErrInvalidSetting = errors.New("invalid setting")
+
+// SettingDefinition describes a single setting of a GameServer.
+type SettingDefinition struct {
+	Category    string      `json:"category,omitempty"`
+	Key         string      `json:"key,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Type        SettingType `json:"type,omitempty"`
+	Default     string      `json:"default,omitempty"`
+	Values      []string    `json:"values,omitempty"` // The allowed values of a select setting.
+	Min         *float64    `json:"min,omitempty"`
+	Max         *float64    `json:"max,omitempty"`
+}
+
+// Validate checks that value is allowed for the setting.
+func (d SettingDefinition) Validate(value string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w %s.%s=%q: %s", ErrInvalidSetting, d.Category, d.Key, value, reason)
+	}
+
+	v := strings.TrimSpace(value)
+	switch d.Type {
+	case SettingBool:
+		if _, err := strconv.ParseBool(v); err != nil {
+			return invalid("must be true, false, 1 or 0")
+		}
+	case SettingInt, SettingFloat:
+		var n float64
+		var err error
+		if d.Type == SettingInt {
+			var i int64
+			i, err = strconv.ParseInt(v, 10, 64)
+			n = float64(i)
+		} else {
+			n, err = strconv.ParseFloat(v, 64)
+		}
+		if err != nil {
+			return invalid(fmt.Sprintf("must be a number of type %s", d.Type))
+		}
+		if d.Min != nil && n < *d.Min {
+			return invalid(fmt.Sprintf("must be at least %v", *d.Min))
+		}
+		if d.Max != nil && n > *d.Max {
+			return invalid(fmt.Sprintf("must be at most %v", *d.Max))
+		}
+	case SettingSelect:
+		for _, allowed := range d.Values {
+			if v == allowed {
+				return nil
+			}
+		}
+		return invalid(fmt.Sprintf("must be one of %q", d.Values))
+	}
+	return nil
+}
+
+// SettingsSchema contains the definitions of the settings of a GameServer,
+// keyed by category and then by setting key.
+type SettingsSchema map[string]map[string]SettingDefinition
+
+// Lookup returns the definition of a setting.
+func (s SettingsSchema) Lookup(category, key string) (SettingDefinition, bool) {
+	d, ok := s[category][key]
+	return d, ok
+}
+
+// Definitions returns every setting definition, sorted by category and key.
+func (s SettingsSchema) Definitions() []SettingDefinition {
+	var defs []SettingDefinition
+	for _, keys := range s {
+		for _, d := range keys {
+			defs = append(defs, d)
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].Category != defs[j].Category {
+			return defs[i].Category < defs[j].Category
+		}
+		return defs[i].Key < defs[j].Key
+	})
+	return defs
+}
+
+// normalize returns value as it is validated, without surrounding spaces
+// unless the setting is text.
+func (s SettingsSchema) normalize(category, key, value string) string {
+	if d, ok := s.Lookup(category, key); ok && d.Type != SettingText {
+		return strings.TrimSpace(value)
+	}
+	return value
+}
+
+// Validate checks that the setting exists and that value is allowed for it.
+func (s SettingsSchema) Validate(category, key, value string) error {
+	d, ok := s.Lookup(category, key)
+	if !ok {
+		return fmt.Errorf("%w: unknown setting %s.%s", ErrInvalidSetting, category, key)
+	}
+	return d.Validate(value)
+}
+
+// GSSettingsSchemaResp contains the response from the settings schema method.
+type GSSettingsSchemaResp struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
+		Settings map[string]map[string]SettingDefinition `json:"settings,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// Schema returns the definitions of the settings available on a GameServer
+// by service ID. Update and Apply fetch it themselves to validate settings,
+// unless it is passed in their options.
+//
+// Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Details
+func (s *GSSettingsService) Schema(serviceID int) (SettingsSchema, *http.Response, error) {
+	u := fmt.Sprintf("services/%v/gameservers/settings/schema", serviceID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var schemaResp *GSSettingsSchemaResp
+	resp, err := s.client.Do(req, &schemaResp)
+	if err != nil {
+		return nil, resp, err
+	}
+	if schemaResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", schemaResp.Status, schemaResp.Message)
+	}
+
+	schema := make(SettingsSchema, len(schemaResp.Data.Settings))
+	for category, keys := range schemaResp.Data.Settings {
+		schema[category] = make(map[string]SettingDefinition, len(keys))
+		for key, d := range keys {
+			d.Category, d.Key = category, key
+			if d.Type == "" {
+				d.Type = SettingText
+			}
+			schema[category][key] = d
+		}
+	}
+
+	return schema, resp, nil
+}
+
+// validationSchema returns schema when it is set, and otherwise the schema
+// of the GameServer, fetching it the first time. It returns nil for games
+// without a settings schema.
+func (s *GSSettingsService) validationSchema(serviceID int, schema SettingsSchema) (SettingsSchema, error) {
+	if schema != nil {
+		return schema, nil
+	}
+
+	s.client.Lock()
+	schema, ok := s.client.settingsSchemas[serviceID]
+	s.client.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema, resp, err := s.Schema(serviceID)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		schema, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting settings schema: %w", err)
+	}
+
+	s.client.Lock()
+	defer s.client.Unlock()
+	if s.client.settingsSchemas == nil {
+		s.client.settingsSchemas = make(map[int]SettingsSchema)
+	}
+	s.client.settingsSchemas[serviceID] = schema
+	return schema, nil
+}
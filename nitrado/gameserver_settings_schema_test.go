@@ -0,0 +1,138 @@
+package nitrado
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const settingsSchemaJSON = `{"status":"success","data":{"settings":{"config":{"hostname":{"name":"Server name","type":"text"},"disableVoN":{"type":"bool","default":"0"},"vonCodecQuality":{"type":"int","min":0,"max":30},"serverTimeAcceleration":{"type":"float","min":0.1,"max":24},"lightingConfig":{"type":"select","values":["0","1"]}},"general":{"bans":{"description":"One player per line"}}}}}`
+
+// TestSettingsSchema_Validate tests the SettingsSchema Validate() method.
+func TestSettingsSchema_Validate(t *testing.T) {
+	lo, hi := 0.0, 30.0
+	schema := SettingsSchema{"config": {
+		"hostname":        {Category: "config", Key: "hostname", Type: SettingText},
+		"disableVoN":      {Category: "config", Key: "disableVoN", Type: SettingBool},
+		"vonCodecQuality": {Category: "config", Key: "vonCodecQuality", Type: SettingInt, Min: &lo, Max: &hi},
+		"timeAccel":       {Category: "config", Key: "timeAccel", Type: SettingFloat, Min: &lo},
+		"lightingConfig":  {Category: "config", Key: "lightingConfig", Type: SettingSelect, Values: []string{"0", "1"}},
+	}}
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "Text", key: "hostname", value: "anything"},
+		{name: "Unknown key", key: "hostnmae", value: "x", wantErr: true},
+		{name: "Bool", key: "disableVoN", value: "1"},
+		{name: "Bool word", key: "disableVoN", value: "false"},
+		{name: "Invalid bool", key: "disableVoN", value: "yes", wantErr: true},
+		{name: "Int", key: "vonCodecQuality", value: "20"},
+		{name: "Int not a number", key: "vonCodecQuality", value: "2.5", wantErr: true},
+		{name: "Int above max", key: "vonCodecQuality", value: "31", wantErr: true},
+		{name: "Float", key: "timeAccel", value: "4.8"},
+		{name: "Float below min", key: "timeAccel", value: "-1", wantErr: true},
+		{name: "Select", key: "lightingConfig", value: "1"},
+		{name: "Select with spaces", key: "lightingConfig", value: " 1 "},
+		{name: "Invalid select", key: "lightingConfig", value: "2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate("config", tt.key, tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSetting)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+// TestGSSettingsService_Schema tests the GSSettingsService Schema() method.
+func TestGSSettingsService_Schema(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/services/7654321/gameservers/settings/schema", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprint(w, settingsSchemaJSON)
+	})
+	mux.HandleFunc("/services/999/gameservers/settings/schema", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"Service not found"}`)
+	})
+
+	schema, _, err := client.GameServersSettings.Schema(7654321)
+	require.Nil(t, err)
+	assert.Len(t, schema.Definitions(), 6)
+	d, ok := schema.Lookup("config", "hostname")
+	assert.True(t, ok)
+	assert.Equal(t, SettingDefinition{Category: "config", Key: "hostname", Name: "Server name", Type: SettingText}, d)
+	d, _ = schema.Lookup("general", "bans")
+	assert.Equal(t, SettingText, d.Type)
+	assert.Equal(t, "config", schema.Definitions()[0].Category)
+
+	_, _, err = client.GameServersSettings.Schema(999)
+	assert.Error(t, err)
+}
+
+// TestGSSettingsService_UpdateValidation tests that Update() and Apply() validate settings against the schema of the game server.
+func TestGSSettingsService_UpdateValidation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &settingsServer{settings: Settings{"config": {"hostname": "My Server", "disableVoN": "0"}}}
+	server.register(t, mux)
+	schemaRequests := 0
+	mux.HandleFunc("/services/7654321/gameservers/settings/schema", func(w http.ResponseWriter, r *http.Request) {
+		schemaRequests++
+		_, _ = fmt.Fprint(w, settingsSchemaJSON)
+	})
+
+	err := client.GameServersSettings.Update(7654321, GSSettingsUpdateOptions{Category: "config", Key: "hostnmae", Value: "x"})
+	assert.ErrorIs(t, err, ErrInvalidSetting)
+	err = client.GameServersSettings.Update(7654321, GSSettingsUpdateOptions{Category: "config", Key: "disableVoN", Value: "yes"})
+	assert.ErrorIs(t, err, ErrInvalidSetting)
+	assert.Empty(t, server.updates)
+
+	// The value is sent as it was validated.
+	require.Nil(t, client.GameServersSettings.Update(7654321, GSSettingsUpdateOptions{Category: "config", Key: "disableVoN", Value: " 1 "}))
+	assert.Equal(t, "1", server.settings["config"]["disableVoN"])
+	assert.Equal(t, 1, schemaRequests)
+
+	// Validation can be skipped, or done with another schema.
+	require.Nil(t, client.GameServersSettings.Update(7654321, GSSettingsUpdateOptions{Category: "config", Key: "hostnmae", Value: "x", SkipValidation: true}))
+	err = client.GameServersSettings.Update(7654321, GSSettingsUpdateOptions{Category: "config", Key: "disableVoN", Value: "1", Schema: SettingsSchema{}})
+	assert.ErrorIs(t, err, ErrInvalidSetting)
+	assert.Len(t, server.updates, 2)
+
+	// Apply validates every change before updating anything.
+	desired := Settings{"config": {"hostname": "New Name", "vonCodecQuality": "99"}}
+	plan, err := client.GameServersSettings.Apply(7654321, desired, GSSettingsApplyOptions{})
+	assert.ErrorIs(t, err, ErrInvalidSetting)
+	require.Len(t, plan.Failed(), 1)
+	assert.Equal(t, "vonCodecQuality", plan.Failed()[0].Key)
+	assert.Len(t, server.updates, 2)
+
+	plan, err = client.GameServersSettings.Apply(7654321, desired, GSSettingsApplyOptions{SkipValidation: true})
+	require.Nil(t, err)
+	assert.Len(t, plan.Changes, 2)
+	assert.Len(t, server.updates, 4)
+	assert.Equal(t, 1, schemaRequests)
+}
+
+// TestGSSettingsService_UpdateWithoutSchema tests that settings of games without a schema are sent without validation.
+func TestGSSettingsService_UpdateWithoutSchema(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &settingsServer{settings: Settings{"config": {"hostname": "My Server"}}}
+	server.register(t, mux)
+
+	require.Nil(t, client.GameServersSettings.Update(7654321, GSSettingsUpdateOptions{Category: "config", Key: "anything", Value: " x "}))
+	assert.Equal(t, " x ", server.settings["config"]["anything"])
+}
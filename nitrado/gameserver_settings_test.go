@@ -1,10 +1,15 @@
 package nitrado
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // JSON minified using https://codebeautify.org/jsonminifier
@@ -107,3 +112,132 @@ func TestGSSettingsService_Update(t *testing.T) {
 		})
 	}
 }
+
+// settingsServer serves the settings of a game server and applies updates to them.
+type settingsServer struct {
+	mu       sync.Mutex
+	settings Settings
+	updates  []string
+	fail     map[string]bool // Keys whose updates fail.
+}
+
+func (s *settingsServer) register(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/services/7654321/gameservers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   map[string]interface{}{"gameserver": map[string]interface{}{"settings": s.settings}},
+		})
+	})
+	mux.HandleFunc("/services/7654321/gameservers/settings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		q := r.URL.Query()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.fail[q.Get("key")] {
+			_, _ = fmt.Fprint(w, `{"status":"error"}`)
+			return
+		}
+		s.settings.Set(q.Get("category"), q.Get("key"), q.Get("value"))
+		s.updates = append(s.updates, q.Get("category")+"."+q.Get("key"))
+		_, _ = fmt.Fprint(w, `{"status":"success"}`)
+	})
+}
+
+// TestGSSettingsService_Get tests the GSSettingsService Get() method.
+func TestGSSettingsService_Get(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/services/7654321/gameservers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"gameserver":{"settings":{"config":{"hostname":"My Server","maxPlayers":60,"pvp":true,"motd":null},"general":{"bans":"Gamer Tag 1"},"quota":[]}}}}`)
+	})
+	mux.HandleFunc("/services/999/gameservers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = fmt.Fprint(w, `{"status":"failure"}`)
+	})
+
+	got, _, err := client.GameServersSettings.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, Settings{
+		"config":  {"hostname": "My Server", "maxPlayers": "60", "pvp": "true", "motd": ""},
+		"general": {"bans": "Gamer Tag 1"},
+	}, got)
+
+	_, _, err = client.GameServersSettings.Get(999)
+	assert.Error(t, err)
+}
+
+// TestSettings_UnmarshalJSON tests that the Settings UnmarshalJSON() method keeps the literal text of values.
+func TestSettings_UnmarshalJSON(t *testing.T) {
+	var got Settings
+	require.Nil(t, json.Unmarshal([]byte(`{"config":{"maxMemory":1000000,"rate":1.50,"big":12345678901234567890,"list":[1, 2]},"empty":[]}`), &got))
+	assert.Equal(t, Settings{
+		"config": {"maxMemory": "1000000", "rate": "1.50", "big": "12345678901234567890", "list": "[1,2]"},
+	}, got)
+	assert.Empty(t, DiffSettings(got, Settings{"config": {"maxMemory": "1000000"}}).Changes)
+
+	require.Nil(t, json.Unmarshal([]byte(`[]`), &got))
+	assert.Equal(t, Settings{}, got)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"config":"not an object"}`), &got))
+}
+
+// TestDiffSettings tests the DiffSettings() function.
+func TestDiffSettings(t *testing.T) {
+	current := Settings{
+		"config":  {"hostname": "My Server", "password": "secret"},
+		"general": {"priority": ""},
+	}
+	desired := Settings{
+		"config":  {"hostname": "New Name", "password": "secret"},
+		"general": {"priority": "Gamer Tag 1", "nolog": "true"},
+	}
+
+	plan := DiffSettings(current, desired)
+	assert.Equal(t, []SettingChange{
+		{Category: "config", Key: "hostname", Old: "My Server", New: "New Name"},
+		{Category: "general", Key: "nolog", New: "true", Added: true},
+		{Category: "general", Key: "priority", Old: "", New: "Gamer Tag 1"},
+	}, plan.Changes)
+	assert.Equal(t, "config.hostname: \"My Server\" -> \"New Name\"\ngeneral.nolog: \"true\"\ngeneral.priority: \"\" -> \"Gamer Tag 1\"\n", plan.String())
+	assert.Empty(t, DiffSettings(current, current).Changes)
+}
+
+// TestGSSettingsService_Apply tests the GSSettingsService Apply() method.
+func TestGSSettingsService_Apply(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	server := &settingsServer{settings: Settings{
+		"config":  {"hostname": "My Server", "password": "secret"},
+		"general": {"priority": ""},
+	}}
+	server.register(t, mux)
+
+	desired := Settings{
+		"config":  {"hostname": "New Name", "password": "secret"},
+		"general": {"priority": "Gamer Tag 1"},
+	}
+
+	plan, err := client.GameServersSettings.Apply(7654321, desired, GSSettingsApplyOptions{DryRun: true})
+	require.Nil(t, err)
+	assert.Len(t, plan.Changes, 2)
+	assert.Empty(t, server.updates)
+
+	server.fail = map[string]bool{"priority": true}
+	plan, err = client.GameServersSettings.Apply(7654321, desired, GSSettingsApplyOptions{})
+	assert.Error(t, err)
+	require.Len(t, plan.Failed(), 1)
+	assert.Equal(t, "priority", plan.Failed()[0].Key)
+	assert.Equal(t, []string{"config.hostname"}, server.updates)
+
+	server.fail = nil
+	plan, err = client.GameServersSettings.Apply(7654321, desired, GSSettingsApplyOptions{})
+	require.Nil(t, err)
+	assert.Len(t, plan.Changes, 1)
+	assert.Equal(t, []string{"config.hostname", "general.priority"}, server.updates)
+	assert.Empty(t, DiffSettings(server.settings, desired).Changes)
+}
@@ -0,0 +1,198 @@
+package nitrado
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DayZSettings is a typed view of the settings of a DayZ GameServer.
+type DayZSettings struct {
+	Hostname                    string  `setting:"config,hostname"`
+	Password                    string  `setting:"config,password"`
+	Mission                     string  `setting:"config,mission"`
+	VonCodecQuality             int     `setting:"config,vonCodecQuality"`
+	DisableVoN                  bool    `setting:"config,disableVoN"`
+	ServerTimeAcceleration      float64 `setting:"config,serverTimeAcceleration"`
+	ServerNightTimeAcceleration float64 `setting:"config,serverNightTimeAcceleration"`
+	ServerTimePersistent        bool    `setting:"config,serverTimePersistent"`
+	UseServerTime               bool    `setting:"config,useServerTime"`
+	CustomServerTime            string  `setting:"config,customServerTime"` // Formatted as YYYY/MM/DD/HH/MM.
+	Disable3rdPerson            bool    `setting:"config,disable3rdPerson"`
+	DisableCrosshair            bool    `setting:"config,disableCrosshair"`
+	EnableMouseAndKeyboard      bool    `setting:"config,enableMouseAndKeyboard"`
+	EnableWhitelist             bool    `setting:"config,enableWhitelist"`
+	AdminLogPlayerHitsOnly      bool    `setting:"config,adminLogPlayerHitsOnly"`
+	AdminLogPlacement           bool    `setting:"config,adminLogPlacement"`
+	AdminLogBuildActions        bool    `setting:"config,adminLogBuildActions"`
+	AdminLogPlayerList          bool    `setting:"config,adminLogPlayerList"`
+	LightingConfig              int     `setting:"config,lightingConfig"`
+	DisablePersonalLight        bool    `setting:"config,disablePersonalLight"`
+	DisableBaseDamage           bool    `setting:"config,disableBaseDamage"`
+	DisableContainerDamage      bool    `setting:"config,disableContainerDamage"`
+	EnableCFGGameplayFile       bool    `setting:"config,enableCfgGameplayFile"`
+
+	ExpertMode     bool     `setting:"general,expertMode"`
+	AdminPassword  string   `setting:"general,admin-password"`
+	RconPassword   string   `setting:"general,rcon-password"`
+	NoLog          bool     `setting:"general,nolog"`
+	ResetMission   bool     `setting:"general,resetmission"`
+	AdditionalMods []string `setting:"general,additionalMods,modlist"` // Parsed like a ModList.
+	Bans           []string `setting:"general,bans"`
+	Whitelist      []string `setting:"general,whitelist"`
+	Priority       []string `setting:"general,priority"`
+}
+
+// MinecraftSettings is a typed view of the settings of a Minecraft GameServer.
+type MinecraftSettings struct {
+	MOTD            string `setting:"config,motd"`
+	MaxPlayers      int    `setting:"config,max-players"`
+	Difficulty      string `setting:"config,difficulty"`
+	GameMode        string `setting:"config,gamemode"`
+	LevelName       string `setting:"config,level-name"`
+	LevelSeed       string `setting:"config,level-seed"`
+	LevelType       string `setting:"config,level-type"`
+	PVP             bool   `setting:"config,pvp"`
+	Hardcore        bool   `setting:"config,hardcore"`
+	OnlineMode      bool   `setting:"config,online-mode"`
+	WhiteList       bool   `setting:"config,white-list"`
+	AllowFlight     bool   `setting:"config,allow-flight"`
+	AllowNether     bool   `setting:"config,allow-nether"`
+	SpawnProtection int    `setting:"config,spawn-protection"`
+	ViewDistance    int    `setting:"config,view-distance"`
+}
+
+// ARKSettings is a typed view of the settings of an ARK: Survival Evolved GameServer.
+type ARKSettings struct {
+	SessionName             string  `setting:"config,SessionName"`
+	ServerPassword          string  `setting:"config,ServerPassword"`
+	ServerAdminPassword     string  `setting:"config,ServerAdminPassword"`
+	Map                     string  `setting:"config,map"`
+	MaxPlayers              int     `setting:"config,MaxPlayers"`
+	ServerPVE               bool    `setting:"config,serverPVE"`
+	ServerHardcore          bool    `setting:"config,ServerHardcore"`
+	DifficultyOffset        float64 `setting:"config,DifficultyOffset"`
+	XPMultiplier            float64 `setting:"config,XPMultiplier"`
+	TamingSpeedMultiplier   float64 `setting:"config,TamingSpeedMultiplier"`
+	HarvestAmountMultiplier float64 `setting:"config,HarvestAmountMultiplier"`
+	AllowThirdPersonPlayer  bool    `setting:"config,AllowThirdPersonPlayer"`
+	ShowMapPlayerLocation   bool    `setting:"config,ShowMapPlayerLocation"`
+	ActiveMods              string  `setting:"general,activeMods"`
+}
+
+// DayZ returns a typed view of DayZ settings.
+func (s Settings) DayZ() (*DayZSettings, error) {
+	var v DayZSettings
+	return &v, s.Decode(&v)
+}
+
+// Minecraft returns a typed view of Minecraft settings.
+func (s Settings) Minecraft() (*MinecraftSettings, error) {
+	var v MinecraftSettings
+	return &v, s.Decode(&v)
+}
+
+// ARK returns a typed view of ARK: Survival Evolved settings.
+func (s Settings) ARK() (*ARKSettings, error) {
+	var v ARKSettings
+	return &v, s.Decode(&v)
+}
+
+// Decode copies settings into the fields of the struct pointed to by v,
+// which can be used to define views for other games. Each field is tagged
+// with `setting:"category,key"` and may be a string, bool, int, float64 or
+// a []string, which is read from a newline separated value. A []string
+// tagged `setting:"category,key,modlist"` is read from a mod list instead,
+// separated by semicolons, commas or newlines like ModsService.List.
+// Booleans accept both "1"/"0" and "true"/"false". Settings which are not set
+// leave their field untouched. Tagged fields must be exported.
+func (s Settings) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("settings can only be decoded into a struct pointer, got %T", v)
+	}
+	rv = rv.Elem()
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		category, key, ok := strings.Cut(field.Tag.Get("setting"), ",")
+		if !ok {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("setting %s: field %s is not exported", field.Tag.Get("setting"), field.Name)
+		}
+		key, option, _ := strings.Cut(key, ",")
+		value, ok := s.Value(category, key)
+		if !ok {
+			continue
+		}
+		if option == "modlist" {
+			mods := newModList(0, value).Mods()
+			entries := make([]string, len(mods))
+			for i, m := range mods {
+				entries[i] = m.name()
+			}
+			value = strings.Join(entries, "\n")
+		}
+		if err := setField(rv.Field(i), value); err != nil {
+			return fmt.Errorf("setting %s.%s: %w", category, key, err)
+		}
+	}
+
+	return nil
+}
+
+// setField parses value into the settings view field f.
+func setField(f reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		if value == "" {
+			f.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int:
+		if value == "" {
+			f.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Float64:
+		if value == "" {
+			f.SetFloat(0)
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", f.Type())
+		}
+		var lines []string
+		for _, line := range strings.Split(value, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		f.Set(reflect.ValueOf(lines))
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package nitrado
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSettings_DayZ tests the Settings DayZ() method.
+func TestSettings_DayZ(t *testing.T) {
+	s := Settings{
+		"config": {
+			"hostname":               "Server Name",
+			"vonCodecQuality":        "20",
+			"disableVoN":             "0",
+			"serverTimeAcceleration": "4.8",
+			"adminLogPlayerList":     "1",
+			"unknownSetting":         "kept",
+		},
+		"general": {
+			"expertMode":     "false",
+			"additionalMods": "1559212036;@CF, 1564026768",
+			"bans":           "Gamer Tag 1\r\nGamer Tag 2\r\n",
+			"priority":       "",
+		},
+	}
+
+	got, err := s.DayZ()
+	require.Nil(t, err)
+	assert.Equal(t, &DayZSettings{
+		Hostname:               "Server Name",
+		VonCodecQuality:        20,
+		ServerTimeAcceleration: 4.8,
+		AdminLogPlayerList:     true,
+		AdditionalMods:         []string{"1559212036", "@CF", "1564026768"},
+		Bans:                   []string{"Gamer Tag 1", "Gamer Tag 2"},
+	}, got)
+	assert.Equal(t, "kept", s["config"]["unknownSetting"])
+}
+
+// TestSettings_Decode tests the Settings Decode() method.
+func TestSettings_Decode(t *testing.T) {
+	type valheim struct {
+		Name     string `setting:"config,server-name"`
+		Public   bool   `setting:"config,public"`
+		Untagged string
+	}
+	type unexported struct {
+		name string `setting:"config,server-name"`
+	}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		v        interface{}
+		want     interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "Custom view",
+			settings: Settings{"config": {"server-name": "Viking", "public": "true"}},
+			v:        &valheim{Untagged: "x"},
+			want:     &valheim{Name: "Viking", Public: true, Untagged: "x"},
+		},
+		{
+			name:     "Minecraft",
+			settings: Settings{"config": {"max-players": "20", "pvp": "true", "gamemode": "survival"}},
+			v:        &MinecraftSettings{},
+			want:     &MinecraftSettings{MaxPlayers: 20, PVP: true, GameMode: "survival"},
+		},
+		{
+			name:     "ARK",
+			settings: Settings{"config": {"SessionName": "Island", "XPMultiplier": "2.5", "serverPVE": "1"}},
+			v:        &ARKSettings{},
+			want:     &ARKSettings{SessionName: "Island", XPMultiplier: 2.5, ServerPVE: true},
+		},
+		{
+			name:     "Invalid bool",
+			settings: Settings{"config": {"pvp": "maybe"}},
+			v:        &MinecraftSettings{},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid int",
+			settings: Settings{"config": {"max-players": "lots"}},
+			v:        &MinecraftSettings{},
+			wantErr:  true,
+		},
+		{
+			name:     "Unexported field",
+			settings: Settings{"config": {"server-name": "Viking"}},
+			v:        &unexported{},
+			wantErr:  true,
+		},
+		{
+			name:    "Not a pointer",
+			v:       MinecraftSettings{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Decode(tt.v)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, tt.v)
+		})
+	}
+}
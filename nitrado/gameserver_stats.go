@@ -3,6 +3,7 @@ package nitrado
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // Generated structs from https://mholt.github.io/json-to-go/
@@ -14,25 +15,41 @@ type GameServerStatsService apiService
 
 // GSStatsResp contains the response object from the stats method on a gameserver
 type GSStatsResp struct {
-	Status string `json:"status"`
-	Data   struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Stats GSStats `json:"stats"`
 	} `json:"data"`
 }
 
 // GSStats contains a stats object for a game server
 type GSStats struct {
-	CPUUsage       [][]float32 `json:"cpuUsage"`
-	CurrentPlayers [][]float32 `json:"currentPlayers"`
-	MaxPlayers     [][]float32 `json:"maxPlayers"`
-	MemoryUsage    [][]float32 `json:"memoryUsage"`
+	CPUUsage       TimeSeries `json:"cpuUsage"`       // CPU usage in percent.
+	CurrentPlayers TimeSeries `json:"currentPlayers"` // Number of players online.
+	MaxPlayers     TimeSeries `json:"maxPlayers"`     // Number of player slots.
+	MemoryUsage    TimeSeries `json:"memoryUsage"`    // Memory usage in MB.
+}
+
+// GSStatsOptions controls the time range of the stats returned by Get.
+type GSStatsOptions struct {
+	Hours int `url:"hours,omitempty"` // Number of hours of stats to return, defaults to the Nitrado default of 24 hours.
+
+	Range    time.Duration `url:"-"` // The time range of stats to return, rounded up to whole hours. Overrides Hours.
+	Interval time.Duration `url:"-"` // Resample the stats to averages over this interval, leaving out intervals without points.
 }
 
 // Get stats from a GameServer by service ID.
 //
 // Nitrado API docs: https://doc.nitrado.net/#api-Gameserver-Stats
-func (s *GameServerStatsService) Get(serviceID int) (*GSStats, *http.Response, error) {
+func (s *GameServerStatsService) Get(serviceID int, opts GSStatsOptions) (*GSStats, *http.Response, error) {
+	if opts.Range > 0 {
+		opts.Hours = int((opts.Range + time.Hour - 1) / time.Hour)
+	}
 	u := fmt.Sprintf("services/%v/gameservers/stats", serviceID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
@@ -44,6 +61,17 @@ func (s *GameServerStatsService) Get(serviceID int) (*GSStats, *http.Response, e
 	if err != nil {
 		return nil, resp, err
 	}
+	if gameServerStatsResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", gameServerStatsResp.Status, gameServerStatsResp.Message)
+	}
+
+	stats := &gameServerStatsResp.Data.Stats
+	if opts.Interval > 0 {
+		stats.CPUUsage = stats.CPUUsage.Resample(opts.Interval)
+		stats.CurrentPlayers = stats.CurrentPlayers.Resample(opts.Interval)
+		stats.MaxPlayers = stats.MaxPlayers.Resample(opts.Interval)
+		stats.MemoryUsage = stats.MemoryUsage.Resample(opts.Interval)
+	}
 
-	return &gameServerStatsResp.Data.Stats, resp, nil
+	return stats, resp, nil
 }
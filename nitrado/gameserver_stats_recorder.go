@@ -0,0 +1,127 @@
+package nitrado
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultRecordInterval is used when StatsRecorder.Interval is not set.
+const defaultRecordInterval = time.Hour
+
+// statsKey identifies a recorded point.
+type statsKey struct {
+	metric StatsMetric
+	time   int64 // Unix milliseconds, as points may have fractional timestamps.
+}
+
+// StatsRecorder polls the stats of a GameServer and appends the new points
+// to a StatsStore, keeping history beyond the window returned by Nitrado.
+// Points which were already recorded are matched by metric and timestamp
+// and skipped, so the polled windows may overlap.
+type StatsRecorder struct {
+	client    *Client
+	serviceID int
+	store     StatsStore
+
+	Interval time.Duration // How often to poll the stats, defaults to 1 hour.
+
+	// OnError is called when polling the stats or appending to the store
+	// fails. The recorder tries again on the next poll.
+	OnError func(error)
+
+	mu   sync.Mutex
+	seen map[statsKey]bool // The recorded points of the last polled window.
+}
+
+// NewStatsRecorder returns a StatsRecorder for the GameServer of the given
+// service, which appends to store.
+func NewStatsRecorder(c *Client, serviceID int, store StatsStore) *StatsRecorder {
+	return &StatsRecorder{client: c, serviceID: serviceID, store: store}
+}
+
+// Run records the stats immediately and then every Interval until ctx is
+// cancelled.
+func (r *StatsRecorder) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultRecordInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Record(); err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Record fetches the stats once and appends the points which have not been
+// recorded yet, returning them.
+func (r *StatsRecorder) Record() ([]StatsRecord, error) {
+	stats, _, err := r.client.GameServerStats.Get(r.serviceID, GSStatsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	records := stats.Records(r.serviceID)
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	oldest, newest := records[0].Time, records[0].Time
+	for _, rec := range records[1:] {
+		if rec.Time.Before(oldest) {
+			oldest = rec.Time
+		}
+		if rec.Time.After(newest) {
+			newest = rec.Time
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The points recorded by a previous run are read back from the store
+	// the first time.
+	if r.seen == nil {
+		stored, err := r.store.Query(r.serviceID, oldest, newest.Add(time.Second))
+		if err != nil {
+			return nil, err
+		}
+		r.seen = make(map[statsKey]bool, len(stored))
+		for _, rec := range stored {
+			r.seen[statsKey{rec.Metric, rec.Time.UnixMilli()}] = true
+		}
+	}
+
+	var fresh []StatsRecord
+	for _, rec := range records {
+		key := statsKey{rec.Metric, rec.Time.UnixMilli()}
+		if r.seen[key] {
+			continue
+		}
+		fresh = append(fresh, rec)
+	}
+	if err := r.store.Append(fresh); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range fresh {
+		r.seen[statsKey{rec.Metric, rec.Time.UnixMilli()}] = true
+	}
+	// Points older than this window will not be returned again.
+	for key := range r.seen {
+		if key.time < oldest.UnixMilli() {
+			delete(r.seen, key)
+		}
+	}
+
+	return fresh, nil
+}
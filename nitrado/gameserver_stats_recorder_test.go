@@ -0,0 +1,70 @@
+package nitrado
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatsRecorder_Record tests the StatsRecorder Record() method.
+func TestStatsRecorder_Record(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var window int32
+	mux.HandleFunc("/services/7654321/gameservers/stats", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch atomic.LoadInt32(&window) {
+		case 0:
+			fmt.Fprint(w, `{"status":"success","data":{"stats":{"cpuUsage":[],"currentPlayers":[[1,1622359920],[2,1622359980]],"maxPlayers":[],"memoryUsage":[]}}}`)
+		case 1:
+			fmt.Fprint(w, `{"status":"success","data":{"stats":{"cpuUsage":[],"currentPlayers":[[2,1622359980],[3,1622359980.5],[5,1622360040]],"maxPlayers":[],"memoryUsage":[]}}}`)
+		default:
+			fmt.Fprint(w, `{"status":"success","data":{"stats":`)
+		}
+	})
+
+	path := filepath.Join(t.TempDir(), "stats.jsonl")
+	store, err := OpenFileStatsStore(path)
+	require.Nil(t, err)
+	defer store.Close()
+
+	recorder := NewStatsRecorder(client, 7654321, store)
+	fresh, err := recorder.Record()
+	require.Nil(t, err)
+	assert.Len(t, fresh, 2)
+	fresh, err = recorder.Record()
+	require.Nil(t, err)
+	assert.Empty(t, fresh)
+
+	// The overlapping point is skipped, but not one within the same second.
+	atomic.StoreInt32(&window, 1)
+	fresh, err = recorder.Record()
+	require.Nil(t, err)
+	require.Len(t, fresh, 2)
+	assert.Equal(t, 3.0, fresh[0].Value)
+	assert.Equal(t, 5.0, fresh[1].Value)
+
+	// A new recorder reads the recorded points back from the store.
+	fresh, err = NewStatsRecorder(client, 7654321, store).Record()
+	require.Nil(t, err)
+	assert.Empty(t, fresh)
+
+	atomic.StoreInt32(&window, 2)
+	_, err = recorder.Record()
+	assert.Error(t, err)
+
+	got, err := store.Query(7654321, time.Unix(0, 0), time.Now())
+	require.Nil(t, err)
+	var values []float64
+	for _, r := range got {
+		values = append(values, r.Value)
+	}
+	assert.Equal(t, []float64{1, 2, 3, 5}, values)
+}
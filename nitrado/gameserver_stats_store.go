@@ -0,0 +1,202 @@
+package nitrado
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StatsMetric identifies the series of GSStats a StatsRecord belongs to.
+type StatsMetric string
+
+// The metrics of GSStats.
+const (
+	StatsCPUUsage       StatsMetric = "cpu_usage"
+	StatsMemoryUsage    StatsMetric = "memory_usage"
+	StatsCurrentPlayers StatsMetric = "current_players"
+	StatsMaxPlayers     StatsMetric = "max_players"
+)
+
+// StatsRecord is a single point of a GSStats series of a service.
+type StatsRecord struct {
+	ServiceID int         `json:"service_id"`
+	Metric    StatsMetric `json:"metric"`
+	Time      time.Time   `json:"time"`
+	Value     float64     `json:"value"`
+}
+
+// Series returns the series of the given metric, or nil for an unknown metric.
+func (s *GSStats) Series(m StatsMetric) TimeSeries {
+	switch m {
+	case StatsCPUUsage:
+		return s.CPUUsage
+	case StatsMemoryUsage:
+		return s.MemoryUsage
+	case StatsCurrentPlayers:
+		return s.CurrentPlayers
+	case StatsMaxPlayers:
+		return s.MaxPlayers
+	}
+	return nil
+}
+
+// Records returns the points of every series as StatsRecords of the given
+// service.
+func (s *GSStats) Records(serviceID int) []StatsRecord {
+	var records []StatsRecord
+	for _, m := range []StatsMetric{StatsCPUUsage, StatsMemoryUsage, StatsCurrentPlayers, StatsMaxPlayers} {
+		for _, p := range s.Series(m) {
+			records = append(records, StatsRecord{ServiceID: serviceID, Metric: m, Time: p.Time, Value: p.Value})
+		}
+	}
+	return records
+}
+
+// StatsStore stores the records of a StatsRecorder.
+type StatsStore interface {
+	// Append adds records to the store.
+	Append(records []StatsRecord) error
+	// Query returns the records of a service at or after from and before
+	// to, ordered by time.
+	Query(serviceID int, from, to time.Time) ([]StatsRecord, error)
+}
+
+// FileStatsStore is a StatsStore which appends records to a file, one JSON
+// object per line. A line left incomplete by a crash is skipped when reading.
+type FileStatsStore struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// OpenFileStatsStore opens, or creates, the file store at path.
+func OpenFileStatsStore(path string) (*FileStatsStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	// Terminate a line left incomplete by a crash, so that it does not
+	// corrupt the next record.
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+			f.Close()
+			return nil, err
+		}
+		if last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return &FileStatsStore{path: path, f: f}, nil
+}
+
+// Append writes records to the end of the file.
+func (s *FileStatsStore) Append(records []StatsRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(buf); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// Query reads the records of a service between from and to from the file.
+func (s *FileStatsStore) Query(serviceID int, from, to time.Time) ([]StatsRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []StatsRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r StatsRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if r.ServiceID == serviceID && !r.Time.Before(from) && r.Time.Before(to) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records, nil
+}
+
+// Close closes the file.
+func (s *FileStatsStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// WriteStatsCSV writes records as CSV with a header row of time, service_id,
+// metric and value. Times are written in RFC 3339 format, with fractional
+// seconds when they have them.
+func WriteStatsCSV(w io.Writer, records []StatsRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "service_id", "metric", "value"}); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			r.Time.UTC().Format(time.RFC3339Nano),
+			strconv.Itoa(r.ServiceID),
+			string(r.Metric),
+			strconv.FormatFloat(r.Value, 'f', -1, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteStatsJSON writes records as a JSON array.
+func WriteStatsJSON(w io.Writer, records []StatsRecord) error {
+	if records == nil {
+		records = []StatsRecord{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
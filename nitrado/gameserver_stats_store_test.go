@@ -0,0 +1,95 @@
+package nitrado
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGSStats_Records tests the GSStats Records() method.
+func TestGSStats_Records(t *testing.T) {
+	stats := GSStats{
+		CPUUsage:       testSeries(1622359920, 12.5),
+		CurrentPlayers: testSeries(1622359920, 3, 4),
+	}
+	assert.Equal(t, []StatsRecord{
+		{ServiceID: 7654321, Metric: StatsCPUUsage, Time: time.Unix(1622359920, 0), Value: 12.5},
+		{ServiceID: 7654321, Metric: StatsCurrentPlayers, Time: time.Unix(1622359920, 0), Value: 3},
+		{ServiceID: 7654321, Metric: StatsCurrentPlayers, Time: time.Unix(1622359980, 0), Value: 4},
+	}, stats.Records(7654321))
+}
+
+// TestGSStats_Series tests the GSStats Series() method.
+func TestGSStats_Series(t *testing.T) {
+	stats := GSStats{MemoryUsage: testSeries(1622359920, 4732)}
+	assert.Equal(t, stats.MemoryUsage, stats.Series(StatsMemoryUsage))
+	assert.Empty(t, stats.Series(StatsCPUUsage))
+	assert.Nil(t, stats.Series("disk_usage"))
+}
+
+// TestFileStatsStore tests appending to and querying a FileStatsStore.
+func TestFileStatsStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.jsonl")
+	store, err := OpenFileStatsStore(path)
+	require.Nil(t, err)
+
+	records := []StatsRecord{
+		{ServiceID: 7654321, Metric: StatsCurrentPlayers, Time: time.Unix(1622359980, 0), Value: 4},
+		{ServiceID: 7654321, Metric: StatsCurrentPlayers, Time: time.Unix(1622359920, 0), Value: 3},
+		{ServiceID: 1234567, Metric: StatsCurrentPlayers, Time: time.Unix(1622359920, 0), Value: 9},
+	}
+	require.Nil(t, store.Append(records))
+	require.Nil(t, store.Close())
+
+	// Simulate a crash in the middle of a write.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.Nil(t, err)
+	_, err = f.WriteString(`{"service_id":7654321,"met`)
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+
+	store, err = OpenFileStatsStore(path)
+	require.Nil(t, err)
+	defer store.Close()
+	require.Nil(t, store.Append([]StatsRecord{{ServiceID: 7654321, Metric: StatsMaxPlayers, Time: time.Unix(1622360040, 0), Value: 10}}))
+
+	got, err := store.Query(7654321, time.Unix(1622359920, 0), time.Unix(1622360100, 0))
+	require.Nil(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, 3.0, got[0].Value)
+	assert.Equal(t, 4.0, got[1].Value)
+	assert.Equal(t, StatsMaxPlayers, got[2].Metric)
+	assert.True(t, got[0].Time.Equal(time.Unix(1622359920, 0)))
+
+	got, err = store.Query(7654321, time.Unix(1622359921, 0), time.Unix(1622360040, 0))
+	require.Nil(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 4.0, got[0].Value)
+}
+
+// TestWriteStats tests the WriteStatsCSV() and WriteStatsJSON() functions.
+func TestWriteStats(t *testing.T) {
+	records := []StatsRecord{
+		{ServiceID: 7654321, Metric: StatsCPUUsage, Time: time.Unix(1622359920, 0), Value: 12.5},
+		{ServiceID: 7654321, Metric: StatsCurrentPlayers, Time: time.Unix(1622359920, 0), Value: 3},
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, WriteStatsCSV(&buf, records))
+	assert.Equal(t, "time,service_id,metric,value\n"+
+		"2021-05-30T07:32:00Z,7654321,cpu_usage,12.5\n"+
+		"2021-05-30T07:32:00Z,7654321,current_players,3\n", buf.String())
+
+	buf.Reset()
+	require.Nil(t, WriteStatsJSON(&buf, records[:1]))
+	assert.JSONEq(t, `[{"service_id":7654321,"metric":"cpu_usage","time":"`+time.Unix(1622359920, 0).Format(time.RFC3339Nano)+`","value":12.5}]`, buf.String())
+
+	buf.Reset()
+	require.Nil(t, WriteStatsJSON(&buf, nil))
+	assert.JSONEq(t, `[]`, buf.String())
+}
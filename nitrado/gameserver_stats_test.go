@@ -5,22 +5,28 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // JSON minified using https://codebeautify.org/jsonminifier
 
-// TestGameserverStats_Get tests the GameServerStatsService List() method.
+// TestGameserverStats_Get tests the GameServerStatsService Get() method.
 func TestGameserverStats_Get(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()
 
 	mux.HandleFunc("/services/7654321/gameservers/stats", func(w http.ResponseWriter, r *http.Request) {
 		testMethod(t, r, "GET")
+		if hours := r.URL.Query().Get("hours"); hours != "" {
+			_, _ = fmt.Fprintf(w, `{"status":"success","data":{"stats":{"cpuUsage":[[%s,1622359920],[20,1622359980],[40,1622360040]],"currentPlayers":[],"maxPlayers":[],"memoryUsage":[]}}}`, hours)
+			return
+		}
 		_, _ = fmt.Fprint(w, `{"status":"success","data":{"stats":{"cpuUsage":[[0,1622359920],[0,1622359980]],"currentPlayers":[[1,1622359920],[0,1622359980]],"maxPlayers":[[32,1622359920],[32,1622359980]],"memoryUsage":[[4732,1622359920],[4734,1622359980]]}}}`)
 	})
 
 	type args struct {
 		serviceID int
+		opts      GSStatsOptions
 	}
 	tests := []struct {
 		name    string
@@ -36,29 +42,47 @@ func TestGameserverStats_Get(t *testing.T) {
 				serviceID: 7654321,
 			},
 			want: GSStats{
-				CPUUsage: [][]float32{
-					{0.0, 1622359920},
-					{0.0, 1622359980},
+				CPUUsage: TimeSeries{
+					{Time: time.Unix(1622359920, 0), Value: 0},
+					{Time: time.Unix(1622359980, 0), Value: 0},
 				},
-				CurrentPlayers: [][]float32{
-					{1.0, 1622359920},
-					{0.0, 1622359980},
+				CurrentPlayers: TimeSeries{
+					{Time: time.Unix(1622359920, 0), Value: 1},
+					{Time: time.Unix(1622359980, 0), Value: 0},
 				},
-				MaxPlayers: [][]float32{
-					{32.0, 1622359920},
-					{32.0, 1622359980},
+				MaxPlayers: TimeSeries{
+					{Time: time.Unix(1622359920, 0), Value: 32},
+					{Time: time.Unix(1622359980, 0), Value: 32},
 				},
-				MemoryUsage: [][]float32{
-					{4732.0, 1622359920},
-					{4734.0, 1622359980},
+				MemoryUsage: TimeSeries{
+					{Time: time.Unix(1622359920, 0), Value: 4732},
+					{Time: time.Unix(1622359980, 0), Value: 4734},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "Last week resampled",
+			s:    client.GameServerStats,
+			args: args{
+				serviceID: 7654321,
+				opts:      GSStatsOptions{Range: 7*24*time.Hour - time.Minute, Interval: 2 * time.Minute},
+			},
+			want: GSStats{
+				CPUUsage: TimeSeries{
+					{Time: time.Unix(1622359920, 0), Value: 94},
+					{Time: time.Unix(1622360040, 0), Value: 40},
+				},
+				CurrentPlayers: TimeSeries{},
+				MaxPlayers:     TimeSeries{},
+				MemoryUsage:    TimeSeries{},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, _, err := tt.s.Get(tt.args.serviceID)
+			got, _, err := tt.s.Get(tt.args.serviceID, tt.args.opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GameServerStatsService.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,192 @@
+package nitrado
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is a single value of a TimeSeries.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// TimeSeries is a series of values ordered by time.
+//
+// Nitrado encodes a time series as a list of [value, unix timestamp] pairs.
+type TimeSeries []Point
+
+// UnmarshalJSON decodes a list of [value, unix timestamp] pairs, sorting the
+// points by time.
+func (ts *TimeSeries) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var pairs [][]json.Number
+	if err := dec.Decode(&pairs); err != nil {
+		return err
+	}
+
+	series := make(TimeSeries, 0, len(pairs))
+	for _, pair := range pairs {
+		if len(pair) != 2 {
+			return fmt.Errorf("time series point %v must be a [value, timestamp] pair", pair)
+		}
+		value, err := pair[0].Float64()
+		if err != nil {
+			return err
+		}
+		// Timestamps are usually whole seconds, but are parsed as numbers so
+		// that a fractional one does not fail the whole series.
+		timestamp, err := pair[1].Float64()
+		if err != nil {
+			return err
+		}
+		seconds, frac := math.Modf(timestamp)
+		series = append(series, Point{Time: time.Unix(int64(seconds), int64(frac*float64(time.Second))), Value: value})
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Time.Before(series[j].Time)
+	})
+	*ts = series
+
+	return nil
+}
+
+// MarshalJSON encodes the series in the format used by Nitrado. Fractional
+// timestamps keep their fraction.
+func (ts TimeSeries) MarshalJSON() ([]byte, error) {
+	pairs := make([][2]interface{}, len(ts))
+	for i, p := range ts {
+		pairs[i] = [2]interface{}{p.Value, unixSeconds(p.Time)}
+	}
+	return json.Marshal(pairs)
+}
+
+// unixSeconds returns t as a number of seconds since the Unix epoch, with a
+// fraction only when t has one.
+func unixSeconds(t time.Time) json.Number {
+	s := strconv.FormatInt(t.Unix(), 10)
+	if ns := t.Nanosecond(); ns != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", ns), "0")
+	}
+	return json.Number(s)
+}
+
+// Min returns the point with the lowest value. It returns false for an empty series.
+func (ts TimeSeries) Min() (Point, bool) {
+	if len(ts) == 0 {
+		return Point{}, false
+	}
+	lowest := ts[0]
+	for _, p := range ts[1:] {
+		if p.Value < lowest.Value {
+			lowest = p
+		}
+	}
+	return lowest, true
+}
+
+// Max returns the point with the highest value. It returns false for an empty series.
+func (ts TimeSeries) Max() (Point, bool) {
+	if len(ts) == 0 {
+		return Point{}, false
+	}
+	highest := ts[0]
+	for _, p := range ts[1:] {
+		if p.Value > highest.Value {
+			highest = p
+		}
+	}
+	return highest, true
+}
+
+// Mean returns the average value. It returns false for an empty series.
+func (ts TimeSeries) Mean() (float64, bool) {
+	if len(ts) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, p := range ts {
+		sum += p.Value
+	}
+	return sum / float64(len(ts)), true
+}
+
+// Percentile returns the p-th percentile of the values, for p between 0 and
+// 100, interpolating between the closest values. It returns false for an
+// empty series or a p outside that range.
+func (ts TimeSeries) Percentile(p float64) (float64, bool) {
+	if len(ts) == 0 || p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, false
+	}
+	values := make([]float64, len(ts))
+	for i, pt := range ts {
+		values[i] = pt.Value
+	}
+	sort.Float64s(values)
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower)), true
+}
+
+// Between returns the points at or after from and before to.
+func (ts TimeSeries) Between(from, to time.Time) TimeSeries {
+	var series TimeSeries
+	for _, p := range ts {
+		if !p.Time.Before(from) && p.Time.Before(to) {
+			series = append(series, p)
+		}
+	}
+	return series
+}
+
+// Downsample reduces the series to at most n points by averaging runs of
+// consecutive points. Each point takes the time of the first point of its
+// run. Series of n points or fewer are returned unchanged.
+func (ts TimeSeries) Downsample(n int) TimeSeries {
+	if n <= 0 || len(ts) <= n {
+		return ts
+	}
+
+	series := make(TimeSeries, 0, n)
+	for i := 0; i < n; i++ {
+		start, end := i*len(ts)/n, (i+1)*len(ts)/n
+		mean, _ := ts[start:end].Mean()
+		series = append(series, Point{Time: ts[start].Time, Value: mean})
+	}
+	return series
+}
+
+// Resample averages the points into buckets of the given interval, aligned
+// to the Unix epoch. Each point takes the start time of its bucket. Buckets
+// without points are left out rather than filled, so consecutive points of
+// the result may be more than interval apart.
+func (ts TimeSeries) Resample(interval time.Duration) TimeSeries {
+	if interval <= 0 || len(ts) == 0 {
+		return ts
+	}
+
+	var series TimeSeries
+	var sum float64
+	var count int
+	var bucket time.Time
+	for _, p := range ts {
+		start := time.Unix(0, 0).Add(p.Time.Sub(time.Unix(0, 0)).Truncate(interval))
+		if count > 0 && !start.Equal(bucket) {
+			series = append(series, Point{Time: bucket, Value: sum / float64(count)})
+			sum, count = 0, 0
+		}
+		bucket = start
+		sum += p.Value
+		count++
+	}
+	return append(series, Point{Time: bucket, Value: sum / float64(count)})
+}
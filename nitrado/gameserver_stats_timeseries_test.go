@@ -0,0 +1,119 @@
+package nitrado
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSeries returns a series with a point every minute from t0.
+func testSeries(t0 int64, values ...float64) TimeSeries {
+	ts := make(TimeSeries, len(values))
+	for i, v := range values {
+		ts[i] = Point{Time: time.Unix(t0+int64(i)*60, 0), Value: v}
+	}
+	return ts
+}
+
+// TestTimeSeries_JSON tests decoding and encoding a TimeSeries.
+func TestTimeSeries_JSON(t *testing.T) {
+	var ts TimeSeries
+	require.Nil(t, json.Unmarshal([]byte(`[[4734.5,1622359980],[4732,1622359920]]`), &ts))
+	assert.Equal(t, TimeSeries{
+		{Time: time.Unix(1622359920, 0), Value: 4732},
+		{Time: time.Unix(1622359980, 0), Value: 4734.5},
+	}, ts)
+
+	data, err := json.Marshal(ts)
+	require.Nil(t, err)
+	assert.Equal(t, `[[4732,1622359920],[4734.5,1622359980]]`, string(data))
+
+	require.Nil(t, json.Unmarshal([]byte(`[[12.5,1622359920.5],[13,1622359980]]`), &ts))
+	assert.Equal(t, TimeSeries{
+		{Time: time.Unix(1622359920, int64(500*time.Millisecond)), Value: 12.5},
+		{Time: time.Unix(1622359980, 0), Value: 13},
+	}, ts)
+	data, err = json.Marshal(ts)
+	require.Nil(t, err)
+	assert.Equal(t, `[[12.5,1622359920.5],[13,1622359980]]`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`[[1]]`), &ts))
+	assert.Error(t, json.Unmarshal([]byte(`[[1,"now"]]`), &ts))
+	assert.Error(t, json.Unmarshal([]byte(`{}`), &ts))
+}
+
+// TestTimeSeries_Aggregates tests the TimeSeries Min(), Max(), Mean() and Percentile() methods.
+func TestTimeSeries_Aggregates(t *testing.T) {
+	ts := testSeries(1622359920, 30, 10, 50, 20, 40)
+
+	lowest, ok := ts.Min()
+	assert.True(t, ok)
+	assert.Equal(t, Point{Time: time.Unix(1622359980, 0), Value: 10}, lowest)
+	highest, ok := ts.Max()
+	assert.True(t, ok)
+	assert.Equal(t, Point{Time: time.Unix(1622360040, 0), Value: 50}, highest)
+	mean, ok := ts.Mean()
+	assert.True(t, ok)
+	assert.Equal(t, 30.0, mean)
+
+	for p, want := range map[float64]float64{0: 10, 50: 30, 90: 46, 100: 50} {
+		got, ok := ts.Percentile(p)
+		assert.True(t, ok)
+		assert.InDelta(t, want, got, 1e-9, "percentile %v", p)
+	}
+	_, ok = ts.Percentile(101)
+	assert.False(t, ok)
+
+	var empty TimeSeries
+	_, ok = empty.Min()
+	assert.False(t, ok)
+	_, ok = empty.Max()
+	assert.False(t, ok)
+	_, ok = empty.Mean()
+	assert.False(t, ok)
+	_, ok = empty.Percentile(50)
+	assert.False(t, ok)
+}
+
+// TestTimeSeries_Between tests the TimeSeries Between() method.
+func TestTimeSeries_Between(t *testing.T) {
+	ts := testSeries(1622359920, 1, 2, 3, 4)
+	assert.Equal(t, ts[1:3], ts.Between(ts[1].Time, ts[3].Time))
+	assert.Empty(t, ts.Between(ts[3].Time.Add(time.Second), ts[3].Time.Add(time.Hour)))
+}
+
+// TestTimeSeries_Downsample tests the TimeSeries Downsample() method.
+func TestTimeSeries_Downsample(t *testing.T) {
+	ts := testSeries(1622359920, 1, 2, 3, 4, 5, 6, 7)
+
+	assert.Equal(t, TimeSeries{
+		{Time: ts[0].Time, Value: 1.5},
+		{Time: ts[2].Time, Value: 3.5},
+		{Time: ts[4].Time, Value: 6},
+	}, ts.Downsample(3))
+	assert.Equal(t, ts, ts.Downsample(7))
+	assert.Equal(t, ts, ts.Downsample(0))
+}
+
+// TestTimeSeries_Resample tests the TimeSeries Resample() method.
+func TestTimeSeries_Resample(t *testing.T) {
+	// 1622359800 is a multiple of 5 minutes.
+	ts := TimeSeries{
+		{Time: time.Unix(1622359800, 0), Value: 1},
+		{Time: time.Unix(1622359860, 0), Value: 3},
+		{Time: time.Unix(1622360100, 0), Value: 10},
+		{Time: time.Unix(1622360700, 0), Value: 7},
+	}
+
+	// The empty bucket starting at 1622360400 is left out.
+	assert.Equal(t, TimeSeries{
+		{Time: time.Unix(1622359800, 0), Value: 2},
+		{Time: time.Unix(1622360100, 0), Value: 10},
+		{Time: time.Unix(1622360700, 0), Value: 7},
+	}, ts.Resample(5*time.Minute))
+	assert.Equal(t, ts, ts.Resample(0))
+	assert.Empty(t, TimeSeries{}.Resample(time.Minute))
+}
@@ -0,0 +1,438 @@
+package nitrado
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyncService mirrors directories between the local disk and the file system
+// of a GameServer.
+type SyncService apiService
+
+// SyncDirection controls which side of a sync is the source of truth.
+type SyncDirection int
+
+const (
+	// SyncPush copies the local directory up to the GameServer.
+	SyncPush SyncDirection = iota
+	// SyncPull copies the GameServer directory down to the local disk.
+	SyncPull
+)
+
+// SyncOp is the type of change made to a single path during a sync.
+type SyncOp string
+
+// The operations a sync can perform.
+const (
+	SyncOpMkdir  SyncOp = "mkdir"
+	SyncOpAdd    SyncOp = "add"
+	SyncOpUpdate SyncOp = "update"
+	SyncOpDelete SyncOp = "delete"
+)
+
+// defaultSyncConcurrency is used when SyncOptions.Concurrency is not set.
+const defaultSyncConcurrency = 4
+
+// SyncOptions controls the behaviour of a sync.
+type SyncOptions struct {
+	Direction SyncDirection
+	LocalDir  string
+	RemoteDir string // Absolute path on the file server, e.g. "/games/ni1_1/noftp/dayzxb/mpmissions".
+
+	// Include and Exclude are path.Match patterns matched against the slash
+	// separated path relative to the synced directory, and against the base
+	// name of the file. When Include is empty every file is included, and
+	// otherwise only the directories containing included files are created.
+	// Exclude patterns are also matched against every parent directory of a
+	// file, so excluding a directory excludes its contents. Exclude takes
+	// precedence over Include.
+	Include []string
+	Exclude []string
+
+	Delete      bool // Delete files from the destination that do not exist in the source.
+	DryRun      bool // Only compute the plan, do not change anything.
+	Concurrency int  // Maximum number of concurrent requests, defaults to 4.
+}
+
+// SyncAction is a single change in a SyncPlan.
+type SyncAction struct {
+	Op SyncOp
+
+	// Path is the slash separated path relative to the synced directory. The
+	// mkdir actions for a missing remote directory, and its missing parents,
+	// have the absolute path on the file server instead.
+	Path    string
+	Size    int64
+	ModTime time.Time
+	Err     error // Set when the action failed while running the plan.
+}
+
+// SyncPlan contains the actions needed to make the destination match the source.
+type SyncPlan struct {
+	Direction SyncDirection
+	Actions   []SyncAction
+}
+
+// String returns the operation and path of the action, aligned for
+// listing.
+func (a SyncAction) String() string {
+	return fmt.Sprintf("%-6s %s", a.Op, a.Path)
+}
+
+// String lists the actions of the plan, one per line in the order they are
+// run, so that a dry run shows what a sync would change.
+func (p *SyncPlan) String() string {
+	return planString(p.Actions)
+}
+
+// Failed returns the actions which returned an error while running the plan.
+func (p *SyncPlan) Failed() []SyncAction {
+	return planFailed(p.Actions, func(a SyncAction) error { return a.Err })
+}
+
+// syncEntry is a file or directory found on either side of a sync.
+type syncEntry struct {
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+// Plan computes the actions needed to sync the directories in opts, without
+// changing anything.
+func (s *SyncService) Plan(svc Service, opts SyncOptions) (*SyncPlan, error) {
+	if opts.LocalDir == "" || !path.IsAbs(opts.RemoteDir) {
+		return nil, fmt.Errorf("local dir must not be blank and remote dir must be absolute. local=%q, remote=%q", opts.LocalDir, opts.RemoteDir)
+	}
+
+	local, err := walkLocal(opts.LocalDir)
+	if err != nil {
+		return nil, err
+	}
+	remote, missing, err := walkRemote((*FileServerService)(s), svc, opts.RemoteDir, opts.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	src, dst := local, remote
+	if opts.Direction == SyncPull {
+		src, dst = remote, local
+	}
+
+	// The filters apply to files. Directories are created when they are not
+	// excluded, or, with Include patterns, when they contain included files.
+	dirs := make(map[string]bool)
+	for p, se := range src {
+		switch {
+		case se.dir && len(opts.Include) == 0 && !opts.excluded(p):
+			dirs[p] = true
+		case !se.dir && opts.included(p):
+			for d := path.Dir(p); d != "."; d = path.Dir(d) {
+				dirs[d] = true
+			}
+		}
+	}
+
+	plan := &SyncPlan{Direction: opts.Direction}
+	if missing && opts.Direction == SyncPush {
+		if plan.Actions, err = missingRemoteDirs((*FileServerService)(s), svc, opts.RemoteDir); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range sortedKeys(src) {
+		se := src[p]
+		de, exists := dst[p]
+		switch {
+		case se.dir:
+			if dirs[p] && !exists {
+				plan.Actions = append(plan.Actions, SyncAction{Op: SyncOpMkdir, Path: p})
+			}
+		case !opts.included(p):
+			// Filtered out.
+		case !exists || de.dir:
+			plan.Actions = append(plan.Actions, SyncAction{Op: SyncOpAdd, Path: p, Size: se.size, ModTime: se.modTime})
+		case se.size != de.size || se.modTime.After(de.modTime):
+			plan.Actions = append(plan.Actions, SyncAction{Op: SyncOpUpdate, Path: p, Size: se.size, ModTime: se.modTime})
+		}
+	}
+
+	if opts.Delete {
+		for _, p := range sortedKeys(dst) {
+			de := dst[p]
+			if _, ok := src[p]; ok || de.dir || !opts.included(p) {
+				continue
+			}
+			plan.Actions = append(plan.Actions, SyncAction{Op: SyncOpDelete, Path: p, Size: de.size, ModTime: de.modTime})
+		}
+	}
+
+	return plan, nil
+}
+
+// Run computes the sync plan for opts and applies it, unless opts.DryRun is
+// set. The returned plan records the error of every failed action, and the
+// returned error summarises any failures.
+func (s *SyncService) Run(svc Service, opts SyncOptions) (*SyncPlan, error) {
+	plan, err := s.Plan(svc, opts)
+	if err != nil || opts.DryRun {
+		return plan, err
+	}
+
+	// Directories are created first and in order, so that parents exist
+	// before any of their children are uploaded. The contents of a directory
+	// which could not be created are not attempted.
+	failedDirs := make(map[string]string)
+	var transfers []*SyncAction
+	for i := range plan.Actions {
+		a := &plan.Actions[i]
+		if dir, ok := failedParent(failedDirs, opts.remotePath(a.Path)); ok {
+			a.Err = fmt.Errorf("directory %q was not created", dir)
+			if a.Op == SyncOpMkdir {
+				failedDirs[opts.remotePath(a.Path)] = dir
+			}
+			continue
+		}
+		if a.Op == SyncOpMkdir {
+			if a.Err = s.apply(svc, opts, a); a.Err != nil {
+				failedDirs[opts.remotePath(a.Path)] = a.Path
+			}
+			continue
+		}
+		transfers = append(transfers, a)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSyncConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, a := range transfers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(a *SyncAction) {
+			defer wg.Done()
+			a.Err = s.apply(svc, opts, a)
+			<-sem
+		}(a)
+	}
+	wg.Wait()
+
+	if failed := plan.Failed(); len(failed) > 0 {
+		return plan, fmt.Errorf("%d of %d sync actions failed, first error: %w", len(failed), len(plan.Actions), failed[0].Err)
+	}
+
+	return plan, nil
+}
+
+// apply performs a single sync action.
+func (s *SyncService) apply(svc Service, opts SyncOptions, a *SyncAction) error {
+	remote := opts.remotePath(a.Path)
+	local := filepath.Join(opts.LocalDir, filepath.FromSlash(a.Path))
+	files := (*FileServerService)(s)
+
+	switch {
+	case a.Op == SyncOpMkdir && opts.Direction == SyncPush:
+		_, err := files.Mkdir(svc, FileServerMkdirOptions{Path: path.Dir(remote), Name: path.Base(remote)})
+		return err
+
+	case a.Op == SyncOpMkdir:
+		return os.MkdirAll(local, 0o755)
+
+	case a.Op == SyncOpDelete && opts.Direction == SyncPush:
+		_, err := files.Delete(svc, FileServerDeleteOptions{Path: remote})
+		return err
+
+	case a.Op == SyncOpDelete:
+		return os.Remove(local)
+
+	case opts.Direction == SyncPush:
+		f, err := os.Open(local)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = files.put(svc, path.Dir(remote), path.Base(remote), f)
+		return err
+
+	default:
+		return pullFile(files, svc, remote, local, a.ModTime)
+	}
+}
+
+// pullFile downloads remote into local and stamps it with the remote
+// modification time, so the next sync sees the files as equal.
+func pullFile(files *FileServerService, svc Service, remote, local string, modTime time.Time) error {
+	body, _, err := files.fetch(svc, remote)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(local)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(local, modTime, modTime)
+}
+
+// remotePath returns the absolute path on the file server of the path p of
+// a SyncAction.
+func (o SyncOptions) remotePath(p string) string {
+	if path.IsAbs(p) {
+		return p
+	}
+	return path.Join(o.RemoteDir, p)
+}
+
+// failedParent returns the failed directory containing the absolute path p,
+// if any, from failed, which maps the failed directories to the one whose
+// failure caused them.
+func failedParent(failed map[string]string, p string) (string, bool) {
+	for d := path.Dir(p); ; d = path.Dir(d) {
+		if dir, ok := failed[d]; ok {
+			return dir, true
+		}
+		if d == "/" || d == "." {
+			return "", false
+		}
+	}
+}
+
+// included reports whether the file at the relative path p passes the
+// include and exclude patterns.
+func (o SyncOptions) included(p string) bool {
+	if o.excluded(p) {
+		return false
+	}
+	return len(o.Include) == 0 || matchAny(o.Include, p)
+}
+
+// excluded reports whether the relative path p, or any of its parent
+// directories, matches an exclude pattern.
+func (o SyncOptions) excluded(p string) bool {
+	for ; p != "."; p = path.Dir(p) {
+		if matchAny(o.Exclude, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny reports whether p or its base name matches any of the patterns.
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(p)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// walkLocal returns every file and directory below root, keyed by slash
+// separated relative path. A missing root is treated as empty.
+func walkLocal(root string) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		entries[filepath.ToSlash(rel)] = syncEntry{size: info.Size(), modTime: info.ModTime(), dir: d.IsDir()}
+		return nil
+	})
+	return entries, err
+}
+
+// walkRemote returns every file and directory below the absolute path root
+// on the GameServer, keyed by slash separated relative path. A missing root
+// is treated as empty, and reported as missing.
+func walkRemote(files *FileServerService, svc Service, root string, concurrency int) (map[string]syncEntry, bool, error) {
+	entries := make(map[string]syncEntry)
+	list, resp, err := files.List(svc, FileServerListOptions{
+		Dir:         root,
+		SortBy:      FileSortNone,
+		Recursive:   true,
+		Concurrency: concurrency,
+	})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return entries, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	prefix := strings.TrimSuffix(root, "/") + "/"
+	for _, f := range list {
+		rel := strings.TrimPrefix(f.Path, prefix)
+		if rel == f.Path || rel == "" {
+			continue
+		}
+		entries[rel] = syncEntry{size: int64(f.Size), modTime: time.Unix(int64(f.ModifiedAt), 0), dir: f.Type == FileTypeDir}
+	}
+	return entries, false, nil
+}
+
+// missingRemoteDirs returns the mkdir actions for the missing directory dir
+// on the GameServer and its missing parents, outermost first.
+func missingRemoteDirs(files *FileServerService, svc Service, dir string) ([]SyncAction, error) {
+	actions := []SyncAction{{Op: SyncOpMkdir, Path: dir}}
+	for d := path.Dir(dir); d != "/"; d = path.Dir(d) {
+		_, resp, err := files.list(svc, FileServerListOptions{Dir: d})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			actions = append([]SyncAction{{Op: SyncOpMkdir, Path: d}}, actions...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	return actions, nil
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]syncEntry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
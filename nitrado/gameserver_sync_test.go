@@ -0,0 +1,238 @@
+package nitrado
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncTestServer registers the file tree from fsTestServer along with upload,
+// mkdir and delete handlers which record the changes made.
+func syncTestServer(t *testing.T, mux *http.ServeMux, serverURL string) (changes func() []string) {
+	fsTestServer(t, mux, serverURL)
+
+	var mu sync.Mutex
+	var recorded []string
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, s)
+	}
+
+	mux.HandleFunc("/services/7654321/gameservers/file_server/upload", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"token":{"url":"%s%s/upload?file=%s/%s","token":"00000000-0000-0000-0000-000000000000"}}}`, serverURL, baseURLPath, r.URL.Query().Get("path"), r.URL.Query().Get("file"))
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		assert.Equal(t, "00000000-0000-0000-0000-000000000000", r.Header.Get("token"))
+		body, _ := io.ReadAll(r.Body)
+		record(fmt.Sprintf("upload %s %s", r.URL.Query().Get("file"), body))
+	})
+	mux.HandleFunc("/services/7654321/gameservers/file_server/mkdir", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		record(fmt.Sprintf("mkdir %s/%s", r.URL.Query().Get("path"), r.URL.Query().Get("name")))
+		_, _ = fmt.Fprint(w, `{"status":"success"}`)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/file_server/delete", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		record(fmt.Sprintf("delete %s", r.URL.Query().Get("path")))
+		_, _ = fmt.Fprint(w, `{"status":"success"}`)
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return recorded
+	}
+}
+
+// writeFile creates a file below dir with the given contents and modification time.
+func writeFile(t *testing.T, dir, name, contents string, modTime time.Time) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	require.Nil(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.Nil(t, os.WriteFile(p, []byte(contents), 0o644))
+	require.Nil(t, os.Chtimes(p, modTime, modTime))
+}
+
+// TestSyncService_Pull tests pulling a remote directory to the local disk.
+func TestSyncService_Pull(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	changes := syncTestServer(t, mux, serverURL)
+
+	local := t.TempDir()
+	writeFile(t, local, "stale.txt", "old", time.Unix(1608633600, 0))
+	writeFile(t, local, "readme.txt", "hello", time.Unix(1608633600, 0))
+
+	opts := SyncOptions{
+		Direction: SyncPull,
+		LocalDir:  local,
+		RemoteDir: "/games/ni1_1",
+		Delete:    true,
+	}
+	svc := Service{ID: 7654321}
+
+	plan, err := client.Sync.Plan(svc, opts)
+	require.Nil(t, err)
+	assert.Equal(t, "mkdir  config\nadd    config/a.ADM\nadd    config/b.ADM\ndelete stale.txt\n", plan.String())
+
+	plan, err = client.Sync.Run(svc, opts)
+	require.Nil(t, err)
+	assert.Len(t, plan.Actions, 4)
+	assert.Empty(t, changes())
+
+	got, err := os.ReadFile(filepath.Join(local, "config", "a.ADM"))
+	require.Nil(t, err)
+	assert.Equal(t, "a log line\n", string(got))
+	_, err = os.Stat(filepath.Join(local, "stale.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	plan, err = client.Sync.Plan(svc, opts)
+	require.Nil(t, err)
+	assert.Empty(t, plan.Actions)
+}
+
+// TestSyncService_Push tests pushing a local directory to the GameServer.
+func TestSyncService_Push(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	changes := syncTestServer(t, mux, serverURL)
+
+	local := t.TempDir()
+	writeFile(t, local, "readme.txt", "hello", time.Unix(1608633600, 0))
+	writeFile(t, local, "config/b.ADM", "changed b\n", time.Unix(1608633600, 0))
+	writeFile(t, local, "mods/@Trader/addon.pbo", "pbo", time.Now())
+	writeFile(t, local, "notes.tmp", "skip me", time.Now())
+
+	opts := SyncOptions{
+		Direction:   SyncPush,
+		LocalDir:    local,
+		RemoteDir:   "/games/ni1_1",
+		Exclude:     []string{"*.tmp", "config/a.ADM"},
+		Delete:      true,
+		Concurrency: 2,
+	}
+	svc := Service{ID: 7654321}
+
+	opts.DryRun = true
+	plan, err := client.Sync.Run(svc, opts)
+	require.Nil(t, err)
+	assert.Equal(t, "update config/b.ADM\nmkdir  mods\nmkdir  mods/@Trader\nadd    mods/@Trader/addon.pbo\n", plan.String())
+	assert.Empty(t, changes())
+
+	opts.DryRun = false
+	_, err = client.Sync.Run(svc, opts)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{
+		"mkdir /games/ni1_1/mods",
+		"mkdir /games/ni1_1/mods/@Trader",
+		"upload /games/ni1_1/config/b.ADM changed b\n",
+		"upload /games/ni1_1/mods/@Trader/addon.pbo pbo",
+	}, changes())
+	assert.Equal(t, "mkdir /games/ni1_1/mods", changes()[0])
+}
+
+// TestSyncService_Plan_filters tests that Include and Exclude patterns filter files, not directories.
+func TestSyncService_Plan_filters(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	syncTestServer(t, mux, serverURL)
+
+	local := t.TempDir()
+	writeFile(t, local, "mods/@Trader/addon.pbo", "pbo", time.Now())
+	writeFile(t, local, "mods/@Trader/readme.txt", "text", time.Now())
+	writeFile(t, local, "keys/trader.bikey", "key", time.Now())
+	require.Nil(t, os.MkdirAll(filepath.Join(local, "empty"), 0o755))
+	svc := Service{ID: 7654321}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    string
+	}{
+		{"none", nil, nil, "mkdir  /games/ni1_1/new\nmkdir  empty\nmkdir  keys\nadd    keys/trader.bikey\nmkdir  mods\nmkdir  mods/@Trader\nadd    mods/@Trader/addon.pbo\nadd    mods/@Trader/readme.txt\n"},
+		{"include", []string{"*.pbo"}, nil, "mkdir  /games/ni1_1/new\nmkdir  mods\nmkdir  mods/@Trader\nadd    mods/@Trader/addon.pbo\n"},
+		{"exclude dir", nil, []string{"mods", "empty"}, "mkdir  /games/ni1_1/new\nmkdir  keys\nadd    keys/trader.bikey\n"},
+		{"exclude nested dir", []string{"*.pbo", "*.bikey"}, []string{"@Trader"}, "mkdir  /games/ni1_1/new\nmkdir  keys\nadd    keys/trader.bikey\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := client.Sync.Plan(svc, SyncOptions{
+				Direction: SyncPush,
+				LocalDir:  local,
+				RemoteDir: "/games/ni1_1/new",
+				Include:   tt.include,
+				Exclude:   tt.exclude,
+			})
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, plan.String())
+		})
+	}
+}
+
+// TestSyncService_Push_newDir tests pushing into a remote directory which does not exist yet.
+func TestSyncService_Push_newDir(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	changes := syncTestServer(t, mux, serverURL)
+
+	local := t.TempDir()
+	writeFile(t, local, "readme.txt", "hello", time.Now())
+	writeFile(t, local, "mods/addon.pbo", "pbo", time.Now())
+	opts := SyncOptions{Direction: SyncPush, LocalDir: local, RemoteDir: "/games/ni1_1/new/mission"}
+	svc := Service{ID: 7654321}
+
+	plan, err := client.Sync.Run(svc, opts)
+	require.Nil(t, err)
+	assert.Equal(t, "mkdir  /games/ni1_1/new\nmkdir  /games/ni1_1/new/mission\nmkdir  mods\nadd    mods/addon.pbo\nadd    readme.txt\n", plan.String())
+	assert.ElementsMatch(t, []string{
+		"mkdir /games/ni1_1/new",
+		"mkdir /games/ni1_1/new/mission",
+		"mkdir /games/ni1_1/new/mission/mods",
+		"upload /games/ni1_1/new/mission/mods/addon.pbo pbo",
+		"upload /games/ni1_1/new/mission/readme.txt hello",
+	}, changes())
+}
+
+// TestSyncService_Run_mkdirFailed tests that the contents of a directory which could not be created are skipped.
+func TestSyncService_Run_mkdirFailed(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	fsTestServer(t, mux, serverURL)
+	uploads := 0
+	mux.HandleFunc("/services/7654321/gameservers/file_server/mkdir", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"Permission denied"}`)
+	})
+	mux.HandleFunc("/services/7654321/gameservers/file_server/upload", func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"unexpected upload"}`)
+	})
+
+	local := t.TempDir()
+	writeFile(t, local, "mods/@Trader/addon.pbo", "pbo", time.Now())
+	plan, err := client.Sync.Run(Service{ID: 7654321}, SyncOptions{Direction: SyncPush, LocalDir: local, RemoteDir: "/games/ni1_1"})
+	require.NotNil(t, err)
+	require.Len(t, plan.Failed(), 3)
+	assert.EqualError(t, plan.Failed()[0].Err, `status "error" ("Permission denied")`)
+	assert.EqualError(t, plan.Failed()[1].Err, `directory "mods" was not created`)
+	assert.EqualError(t, plan.Failed()[2].Err, `directory "mods" was not created`)
+	assert.Zero(t, uploads)
+}
+
+// TestSyncService_Plan_invalid tests that Plan rejects invalid options.
+func TestSyncService_Plan_invalid(t *testing.T) {
+	client := NewClient(token)
+	_, err := client.Sync.Plan(Service{ID: 7654321}, SyncOptions{LocalDir: "x", RemoteDir: "games/ni1_1"})
+	assert.NotNil(t, err)
+}
@@ -66,40 +66,40 @@ func TestGameServersService_Get(t *testing.T) {
 	assert.Equal(t, "ni2_2_DB", got.Credentials.Mysql.Username)
 	assert.Equal(t, "ABC123z", got.Credentials.Mysql.Password)
 	assert.Equal(t, "ni2_2_DB", got.Credentials.Mysql.Database)
-	// Settings.Config
-	assert.Equal(t, "Server Name", got.Settings.Config.Hostname)
-	assert.Equal(t, "20", got.Settings.Config.VonCodecQuality)
-	assert.Equal(t, "0", got.Settings.Config.DisableVoN)
-	assert.Equal(t, "", got.Settings.Config.Password)
-	assert.Equal(t, "4.8", got.Settings.Config.ServerTimeAcceleration)
-	assert.Equal(t, "5", got.Settings.Config.ServerNightTimeAcceleration)
-	assert.Equal(t, "0", got.Settings.Config.ServerTimePersistent)
-	assert.Equal(t, "0", got.Settings.Config.Disable3RdPerson)
-	assert.Equal(t, "0", got.Settings.Config.DisableCrosshair)
-	assert.Equal(t, "0", got.Settings.Config.UseServerTime)
-	assert.Equal(t, "2020/07/01/04/00", got.Settings.Config.CustomServerTime)
-	assert.Equal(t, "1", got.Settings.Config.EnableMouseAndKeyboard)
-	assert.Equal(t, "0", got.Settings.Config.EnableWhitelist)
-	assert.Equal(t, "dayzOffline.chernarusplus", got.Settings.Config.Mission)
-	assert.Equal(t, "1", got.Settings.Config.AdminLogPlayerHitsOnly)
-	assert.Equal(t, "1", got.Settings.Config.AdminLogPlacement)
-	assert.Equal(t, "1", got.Settings.Config.AdminLogBuildActions)
-	assert.Equal(t, "1", got.Settings.Config.AdminLogPlayerList)
-	assert.Equal(t, "1", got.Settings.Config.LightingConfig)
-	assert.Equal(t, "0", got.Settings.Config.DisablePersonalLight)
-	assert.Equal(t, "0", got.Settings.Config.DisableBaseDamage)
-	assert.Equal(t, "1", got.Settings.Config.DisableContainerDamage)
-	assert.Equal(t, "1", got.Settings.Config.EnableCFGGameplayFile)
-	// Settings.General
-	assert.Equal(t, "false", got.Settings.General.ExpertMode)
-	assert.Equal(t, "ABC_123z", got.Settings.General.AdminPassword)
-	assert.Equal(t, "false", got.Settings.General.Nolog)
-	assert.Equal(t, "", got.Settings.General.RconPassword)
-	assert.Equal(t, "", got.Settings.General.AdditionalMods)
-	assert.Equal(t, "Gamer Tag 1\r\nGamer Tag 2", got.Settings.General.Bans)
-	assert.Equal(t, "Gamer Tag 1\r\nGamer Tag 2", got.Settings.General.Whitelist)
-	assert.Equal(t, "false", got.Settings.General.Resetmission)
-	assert.Equal(t, "Gamer Tag 1\r\nGamer Tag 2", got.Settings.General.Priority)
+	// Settings config
+	assert.Equal(t, "Server Name", got.Settings["config"]["hostname"])
+	assert.Equal(t, "20", got.Settings["config"]["vonCodecQuality"])
+	assert.Equal(t, "0", got.Settings["config"]["disableVoN"])
+	assert.Equal(t, "", got.Settings["config"]["password"])
+	assert.Equal(t, "4.8", got.Settings["config"]["serverTimeAcceleration"])
+	assert.Equal(t, "5", got.Settings["config"]["serverNightTimeAcceleration"])
+	assert.Equal(t, "0", got.Settings["config"]["serverTimePersistent"])
+	assert.Equal(t, "0", got.Settings["config"]["disable3rdPerson"])
+	assert.Equal(t, "0", got.Settings["config"]["disableCrosshair"])
+	assert.Equal(t, "0", got.Settings["config"]["useServerTime"])
+	assert.Equal(t, "2020/07/01/04/00", got.Settings["config"]["customServerTime"])
+	assert.Equal(t, "1", got.Settings["config"]["enableMouseAndKeyboard"])
+	assert.Equal(t, "0", got.Settings["config"]["enableWhitelist"])
+	assert.Equal(t, "dayzOffline.chernarusplus", got.Settings["config"]["mission"])
+	assert.Equal(t, "1", got.Settings["config"]["adminLogPlayerHitsOnly"])
+	assert.Equal(t, "1", got.Settings["config"]["adminLogPlacement"])
+	assert.Equal(t, "1", got.Settings["config"]["adminLogBuildActions"])
+	assert.Equal(t, "1", got.Settings["config"]["adminLogPlayerList"])
+	assert.Equal(t, "1", got.Settings["config"]["lightingConfig"])
+	assert.Equal(t, "0", got.Settings["config"]["disablePersonalLight"])
+	assert.Equal(t, "0", got.Settings["config"]["disableBaseDamage"])
+	assert.Equal(t, "1", got.Settings["config"]["disableContainerDamage"])
+	assert.Equal(t, "1", got.Settings["config"]["enableCfgGameplayFile"])
+	// Settings general
+	assert.Equal(t, "false", got.Settings["general"]["expertMode"])
+	assert.Equal(t, "ABC_123z", got.Settings["general"]["admin-password"])
+	assert.Equal(t, "false", got.Settings["general"]["nolog"])
+	assert.Equal(t, "", got.Settings["general"]["rcon-password"])
+	assert.Equal(t, "", got.Settings["general"]["additionalMods"])
+	assert.Equal(t, "Gamer Tag 1\r\nGamer Tag 2", got.Settings["general"]["bans"])
+	assert.Equal(t, "Gamer Tag 1\r\nGamer Tag 2", got.Settings["general"]["whitelist"])
+	assert.Equal(t, "false", got.Settings["general"]["resetmission"])
+	assert.Equal(t, "Gamer Tag 1\r\nGamer Tag 2", got.Settings["general"]["priority"])
 
 	// Query
 	assert.Equal(t, "Server Name", got.Query.ServerName)
@@ -110,6 +110,23 @@ func TestGameServersService_Get(t *testing.T) {
 	assert.Equal(t, 50, got.Query.PlayerMax)
 }
 
+// TestGameServersService_Get_error tests that the GameServersService Get() method returns API errors.
+func TestGameServersService_Get_error(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.Retries = 1
+
+	mux.HandleFunc("/services/7654321/gameservers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprint(w, `{"status":"error","message":"Service unavailable"}`)
+	})
+
+	got, resp, err := client.GameServers.Get(7654321)
+	assert.EqualError(t, err, `status "error" ("Service unavailable")`)
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
 // TestGameServersService_Restart tests the GameServersService Restart() method.
 func TestGameServersService_Restart(t *testing.T) {
 	client, mux, _, teardown := setup()
@@ -4,8 +4,9 @@ package nitrado
 
 // FileBookmarks contains the list of file locations on a game server
 type FileBookmarks struct {
-	Status string `json:"status"`
-	Data   struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Bookmarks []string `json:"bookmarks"`
 	} `json:"data"`
 }
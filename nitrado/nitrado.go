@@ -17,6 +17,7 @@ import (
 
 const (
 	defaultBaseURI string        = "https://api.nitrado.net/"
+	defaultLiveURI string        = "wss://websocket.nitrado.net/"
 	retryCount     int           = 10
 	retryDelay     time.Duration = 2 * time.Second
 	userAgent      string        = "go-nitrado"
@@ -33,15 +34,31 @@ type Client struct {
 	// always be specified with a trailing slash.
 	BaseURI *url.URL
 
+	// Websocket URL used by the LiveService. Defaults to the public Nitrado websocket.
+	LiveURI *url.URL
+
+	// Requests which fail to be sent, are rate limited or return a server
+	// error are sent up to Retries times, RetryDelay apart. Defaults to 10
+	// times, 2 seconds apart. Other client errors are returned at once.
+	Retries    int
+	RetryDelay time.Duration
+
 	common apiService // Reuse a single struct instead of allocating one for each service on the heap.
 
+	settingsSchemas map[int]SettingsSchema // Settings schemas used to validate updates, by service ID.
+
 	// Services used for talking to different parts of the Nitrado API.
+	AccessLists         *AccessListService
+	Console             *ConsoleService
 	FileServerService   *FileServerService
 	GameServers         *GameServersService
 	GameServersSettings *GSSettingsService
 	GameServerStats     *GameServerStatsService
+	Live                *LiveService
+	Mods                *ModsService
 	PlayerListService   *PlayerListService
 	Services            *ServicesService
+	Sync                *SyncService
 }
 
 type apiService struct {
@@ -70,6 +87,27 @@ func addOptions(s string, opts interface{}) (string, error) {
 	return u.String(), nil
 }
 
+// planString renders the steps of a plan, such as a SyncPlan, one per line.
+func planString[T fmt.Stringer](steps []T) string {
+	var b strings.Builder
+	for _, step := range steps {
+		b.WriteString(step.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// planFailed returns the steps of a plan for which err returns an error.
+func planFailed[T any](steps []T, err func(T) error) []T {
+	var failed []T
+	for _, step := range steps {
+		if err(step) != nil {
+			failed = append(failed, step)
+		}
+	}
+	return failed
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURI of the Client.
 // Relative URLs should always be specified without a preceding slash. If
@@ -120,12 +158,20 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	// Do the request
 	var err error
 	var resp *http.Response
-	for i := 0; i < retryCount; i++ {
+	for i := 0; i < c.Retries || i == 0; i++ {
+		if i > 0 {
+			time.Sleep(c.RetryDelay)
+		}
 		resp, err = c.client.Do(req)
-		if err == nil && resp.StatusCode < 400 {
+		if err == nil && !retryable(resp.StatusCode) {
 			break
 		}
-		time.Sleep(retryDelay)
+		if err == nil && i+1 < c.Retries {
+			resp.Body.Close() // Discard the failed response before retrying.
+		}
+	}
+	if err != nil {
+		return resp, err
 	}
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
@@ -144,25 +190,51 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	return resp, err
 }
 
+// retryable reports whether a request which returned status may succeed
+// when it is sent again.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
 // NewClient creates a new instance of a NitradoAPI
 func NewClient(apiToken string) *Client {
+	return NewClientWithHTTPClient(apiToken, nil)
+}
+
+// NewClientWithHTTPClient creates a new instance of a NitradoAPI which sends
+// its requests, including file downloads and uploads, with httpClient. This
+// allows a custom transport, such as a proxy or a recording transport in
+// tests. A new http.Client is used when httpClient is nil.
+func NewClientWithHTTPClient(apiToken string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
 	baseURL, _ := url.Parse(defaultBaseURI)
+	liveURL, _ := url.Parse(defaultLiveURI)
 
 	c := &Client{
-		BaseURI:   baseURL,
-		token:     apiToken,
-		client:    &http.Client{},
-		UserAgent: userAgent,
+		BaseURI:    baseURL,
+		LiveURI:    liveURL,
+		token:      apiToken,
+		client:     httpClient,
+		UserAgent:  userAgent,
+		Retries:    retryCount,
+		RetryDelay: retryDelay,
 	}
 
 	c.common.client = c
 
+	c.AccessLists = (*AccessListService)(&c.common)
+	c.Console = (*ConsoleService)(&c.common)
 	c.FileServerService = (*FileServerService)(&c.common)
 	c.GameServers = (*GameServersService)(&c.common)
 	c.GameServersSettings = (*GSSettingsService)(&c.common)
 	c.Services = (*ServicesService)(&c.common)
 	c.GameServerStats = (*GameServerStatsService)(&c.common)
+	c.Live = (*LiveService)(&c.common)
+	c.Mods = (*ModsService)(&c.common)
 	c.PlayerListService = (*PlayerListService)(&c.common)
+	c.Sync = (*SyncService)(&c.common)
 
 	return c
 }
@@ -87,6 +87,22 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+// TestNewClientWithHTTPClient tests the NewClientWithHTTPClient() method.
+func TestNewClientWithHTTPClient(t *testing.T) {
+	hc := &http.Client{}
+	c := NewClientWithHTTPClient(token, hc)
+	if c.client != hc {
+		t.Error("NewClientWithHTTPClient did not use the given http.Client")
+	}
+	if got, want := c.BaseURI.String(), defaultBaseURI; got != want {
+		t.Errorf("NewClientWithHTTPClient BaseURI is %v, want %v", got, want)
+	}
+
+	if c := NewClientWithHTTPClient(token, nil); c.client == nil {
+		t.Error("NewClientWithHTTPClient returned a nil http.Client")
+	}
+}
+
 // TestNewRequest tests the NewRequest() method using various input.
 func TestNewRequest(t *testing.T) {
 	c := NewClient(token)
@@ -152,6 +168,68 @@ func TestNewRequest_badMethod(t *testing.T) {
 	}
 }
 
+// TestDo_retry tests that the Do() method retries failed requests Retries times.
+func TestDo_retry(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.RetryDelay = 0
+
+	calls := 0
+	mux.HandleFunc("/retry", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, `{"status":"error"}`, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success"}`)
+	})
+
+	req, _ := client.NewRequest("GET", "retry", nil)
+	var v struct{ Status string }
+	if _, err := client.Do(req, &v); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if calls != 3 || v.Status != "success" {
+		t.Errorf("Do made %d requests with status %q, want 3 with status \"success\"", calls, v.Status)
+	}
+
+	failures := 0
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		failures++
+		http.Error(w, `{"status":"error"}`, http.StatusServiceUnavailable)
+	})
+
+	client.Retries = 2
+	req, _ = client.NewRequest("GET", "fail", nil)
+	resp, _ := client.Do(req, &v)
+	if failures != 2 || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Do made %d requests with status %d, want 2 with status 503", failures, resp.StatusCode)
+	}
+
+	// Client errors other than rate limiting are not retried.
+	tests := []struct {
+		status int
+		want   int
+	}{
+		{status: http.StatusUnauthorized, want: 1},
+		{status: http.StatusNotFound, want: 1},
+		{status: http.StatusUnprocessableEntity, want: 1},
+		{status: http.StatusTooManyRequests, want: 2},
+	}
+	for _, tt := range tests {
+		requests := 0
+		mux.HandleFunc(fmt.Sprintf("/status/%d", tt.status), func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			http.Error(w, `{"status":"error"}`, tt.status)
+		})
+		req, _ = client.NewRequest("GET", fmt.Sprintf("status/%d", tt.status), nil)
+		resp, _ = client.Do(req, &v)
+		if requests != tt.want || resp.StatusCode != tt.status {
+			t.Errorf("Do made %d requests with status %d, want %d with status %d", requests, resp.StatusCode, tt.want, tt.status)
+		}
+	}
+}
+
 // Test_addOptions_empty tests the addOptions() function with an empty opts parameter.
 func Test_addOptions_empty(t *testing.T) {
 	if _, err := addOptions("test/path", ""); err == nil {
@@ -43,16 +43,18 @@ type Service struct {
 
 // ServiceListResp contains a list of services
 type ServiceListResp struct {
-	Status string `json:"status,omitempty"`
-	Data   struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Services []Service `json:"services,omitempty"`
 	} `json:"data,omitempty"`
 }
 
 // ServiceDetailResp contains a list of services
 type ServiceDetailResp struct {
-	Status string `json:"status,omitempty"`
-	Data   struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    struct {
 		Service Service `json:"service,omitempty"`
 	} `json:"data,omitempty"`
 }
@@ -72,6 +74,9 @@ func (s *ServicesService) List() (*[]Service, *http.Response, error) {
 	if err != nil {
 		return services, resp, err
 	}
+	if serviceListResp.Status != "success" {
+		return services, resp, fmt.Errorf("status %q (%q)", serviceListResp.Status, serviceListResp.Message)
+	}
 
 	services = &serviceListResp.Data.Services
 
@@ -93,6 +98,9 @@ func (s *ServicesService) Get(id int) (*Service, *http.Response, error) {
 	if err != nil {
 		return nil, resp, err
 	}
+	if serviceDetailResp.Status != "success" {
+		return nil, resp, fmt.Errorf("status %q (%q)", serviceDetailResp.Status, serviceDetailResp.Message)
+	}
 
 	return &serviceDetailResp.Data.Service, resp, nil
 }
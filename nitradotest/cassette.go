@@ -0,0 +1,371 @@
+package nitradotest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redacted replaces scrubbed values in a cassette.
+const Redacted = "REDACTED"
+
+// DefaultScrubKeys are the JSON keys and query parameters whose values are
+// scrubbed from cassettes, when a key contains one of them, ignoring case.
+// They cover the API token, the file server tokens, and credentials such as
+// GameServer.Credentials.Ftp.Password and password settings.
+var DefaultScrubKeys = []string{"password", "token", "secret"}
+
+// scrubbedHeaders are the headers whose values are scrubbed from cassettes.
+var scrubbedHeaders = []string{"Authorization", "Token", "Cookie", "Set-Cookie"}
+
+// Interaction is a request and its response in a Cassette.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request. The body is not recorded, as it
+// is not used to match requests.
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// CassetteResponse is a recorded response. Bodies which are not valid UTF-8
+// are kept in BodyBase64.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// Cassette is a list of recorded interactions, saved as JSON.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette reads a cassette from a file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("reading cassette %q: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to a file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Recorder is an http.RoundTripper which records every request and response
+// to a cassette file, scrubbing credentials. Use it with
+// nitrado.NewClientWithHTTPClient:
+//
+//	rec := nitradotest.NewRecorder("testdata/restart.json")
+//	client := nitrado.NewClientWithHTTPClient(token, &http.Client{Transport: rec})
+type Recorder struct {
+	path string
+
+	// Transport sends the requests, defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// ScrubKeys are the JSON keys and query parameters to scrub, defaults
+	// to DefaultScrubKeys.
+	ScrubKeys []string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder which writes to the cassette at path,
+// replacing it.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// RoundTrip sends the request and records it with its response. The
+// cassette is saved after every interaction.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	keys := r.ScrubKeys
+	if keys == nil {
+		keys = DefaultScrubKeys
+	}
+	in := Interaction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    scrubURL(req.URL, keys).String(),
+			Header: scrubHeader(req.Header),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+		},
+	}
+	body = scrubJSON(body, keys)
+	if utf8.Valid(body) {
+		in.Response.Body = string(body)
+	} else {
+		in.Response.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, fmt.Errorf("saving cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// ErrNoInteraction is returned by a Replayer for a request which is not in
+// its cassette.
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Replayer is an http.RoundTripper which responds from a cassette without
+// making any requests. Requests are matched by method, path and query,
+// with the query scrubbed like the Recorder does. Repeated requests are
+// answered with the recorded interactions in order, and the last one is
+// repeated once they run out.
+type Replayer struct {
+	// ScrubKeys must match the ScrubKeys used to record, defaults to
+	// DefaultScrubKeys.
+	ScrubKeys []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer returns a Replayer for the cassette at path.
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{cassette: c, used: make([]bool, len(c.Interactions))}, nil
+}
+
+// RoundTrip responds with the next recorded interaction matching req.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	keys := r.ScrubKeys
+	if keys == nil {
+		keys = DefaultScrubKeys
+	}
+	want := matchKey(req.Method, scrubURL(req.URL, keys))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, in := range r.cassette.Interactions {
+		u, err := url.Parse(in.Request.URL)
+		if err != nil || matchKey(in.Request.Method, u) != want {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return in.Response.response(req)
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+	}
+	return r.cassette.Interactions[last].Response.response(req)
+}
+
+// response returns the recorded response for req.
+func (c CassetteResponse) response(req *http.Request) (*http.Response, error) {
+	body := []byte(c.Body)
+	if c.BodyBase64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(c.BodyBase64); err != nil {
+			return nil, err
+		}
+	}
+	header := c.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", c.StatusCode, http.StatusText(c.StatusCode)),
+		StatusCode:    c.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// CassetteTransport replays the cassette at path when it exists, and
+// otherwise records a new one with transport. Delete the cassette to record
+// it again.
+func CassetteTransport(path string, transport http.RoundTripper) (http.RoundTripper, error) {
+	if _, err := os.Stat(path); err == nil {
+		return NewReplayer(path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	rec := NewRecorder(path)
+	rec.Transport = transport
+	return rec, nil
+}
+
+// matchKey identifies a request by method, path and sorted query.
+func matchKey(method string, u *url.URL) string {
+	return method + " " + u.Path + "?" + u.Query().Encode()
+}
+
+// scrubURL returns a copy of u with the values of sensitive query
+// parameters scrubbed. The value of a setting is scrubbed when its key is
+// sensitive.
+func scrubURL(u *url.URL, keys []string) *url.URL {
+	q := u.Query()
+	for name := range q {
+		if sensitive(name, keys) {
+			q.Set(name, Redacted)
+		}
+	}
+	if q.Get("value") != "" && sensitive(q.Get("key"), keys) {
+		q.Set("value", Redacted)
+	}
+	scrubbed := *u
+	scrubbed.RawQuery = q.Encode()
+	scrubbed.User = nil
+	return &scrubbed
+}
+
+// scrubHeader returns a copy of h with credentials scrubbed.
+func scrubHeader(h http.Header) http.Header {
+	scrubbed := h.Clone()
+	for _, name := range scrubbedHeaders {
+		if scrubbed.Get(name) != "" {
+			scrubbed.Set(name, Redacted)
+		}
+	}
+	return scrubbed
+}
+
+// scrubJSON replaces the string values of sensitive keys in a JSON body,
+// and scrubs the query of string values which are URLs, such as file server
+// download links. Bodies which are not JSON are returned unchanged.
+func scrubJSON(body []byte, keys []string) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !scrubValue(v, keys) {
+		return body
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// scrubValue scrubs v in place, reporting whether anything was scrubbed.
+func scrubValue(v interface{}, keys []string) bool {
+	scrubbed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if s, ok := child.(string); ok && s != "" && sensitive(k, keys) {
+				v[k] = Redacted
+				scrubbed = true
+				continue
+			}
+			if s, ok := child.(string); ok {
+				if u, ok := scrubURLString(s, keys); ok {
+					v[k] = u
+					scrubbed = true
+				}
+				continue
+			}
+			if scrubValue(child, keys) {
+				scrubbed = true
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if s, ok := child.(string); ok {
+				if u, ok := scrubURLString(s, keys); ok {
+					v[i] = u
+					scrubbed = true
+				}
+				continue
+			}
+			if scrubValue(child, keys) {
+				scrubbed = true
+			}
+		}
+	}
+	return scrubbed
+}
+
+// scrubURLString scrubs s like scrubURL when it is an absolute URL with a
+// query, reporting whether anything was scrubbed.
+func scrubURLString(s string, keys []string) (string, bool) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" || (u.RawQuery == "" && u.User == nil) {
+		return s, false
+	}
+	scrubbed := scrubURL(u, keys)
+	if scrubbed.RawQuery == u.Query().Encode() && u.User == nil {
+		return s, false
+	}
+	return scrubbed.String(), true
+}
+
+// sensitive reports whether key contains one of keys, ignoring case.
+func sensitive(key string, keys []string) bool {
+	key = strings.ToLower(key)
+	for _, k := range keys {
+		if strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package nitradotest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cassetteClient returns a client for server which uses transport.
+func cassetteClient(server *Server, transport http.RoundTripper) *nitrado.Client {
+	c := nitrado.NewClientWithHTTPClient(server.Token, &http.Client{Transport: transport})
+	c.BaseURI, _ = url.Parse(server.URL())
+	return c
+}
+
+// TestCassette tests recording and replaying a cassette.
+func TestCassette(t *testing.T) {
+	server := newServer(t)
+	gs, _ := server.GameServer(7654321)
+	gs.Credentials.Ftp.Password = "ftp-secret"
+	gs.Credentials.Mysql.Password = "mysql-secret"
+	svc := nitrado.Service{ID: 7654321, WebsocketToken: "ws-secret"}
+	server.AddGameServer(svc, gs)
+	server.SetSetting(7654321, "general", "rcon-password", "rcon-secret")
+	server.WriteFile(7654321, "/games/ni1_1/noftp/dayzxb/config/serverDZ.cfg", []byte(`hostname = "My Server";`))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// The Nitrado file server passes the download token in the query of the
+	// download link.
+	const downloadToken = "0f8c4e2a-download-secret"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/1/gameservers/file_server/download":
+			_, _ = fmt.Fprintf(w, `{"status":"success","data":{"token":{"url":"http://%s/download/?token=%s","token":"%s"}}}`, r.Host, downloadToken, downloadToken)
+		case "/download/":
+			_, _ = fmt.Fprint(w, "admin log")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	download := func(transport http.RoundTripper) string {
+		client := nitrado.NewClientWithHTTPClient(server.Token, &http.Client{Transport: transport})
+		client.BaseURI, _ = url.Parse(api.URL + "/")
+		link, _, err := client.FileServerService.Download(nitrado.Service{ID: 1}, nitrado.FileServerDownloadOptions{File: "/games/ni1_1/noftp/dayzxb/config/DayZServer_X1_x64.ADM"})
+		require.Nil(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(link)
+		require.Nil(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+		return string(body)
+	}
+
+	// Record.
+	transport, err := CassetteTransport(path, nil)
+	require.Nil(t, err)
+	require.IsType(t, &Recorder{}, transport)
+	client := cassetteClient(server, transport)
+	recorded, _, err := client.GameServers.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, "ftp-secret", recorded.Credentials.Ftp.Password)
+	require.Nil(t, client.GameServersSettings.Update(7654321, nitrado.GSSettingsUpdateOptions{Category: "general", Key: "rcon-password", Value: "new-secret"}))
+	require.Nil(t, client.GameServers.Restart(7654321))
+	services, _, err := client.Services.List()
+	require.Nil(t, err)
+	data, err := fs.ReadFile(nitrado.FS(client, svc), "games/ni1_1/noftp/dayzxb/config/serverDZ.cfg")
+	require.Nil(t, err)
+	assert.Equal(t, "admin log", download(transport))
+	api.Close()
+
+	cassette, err := os.ReadFile(path)
+	require.Nil(t, err)
+	for _, secret := range []string{DefaultToken, "ftp-secret", "mysql-secret", "ws-secret", "rcon-secret", "new-secret", downloadToken} {
+		assert.NotContains(t, string(cassette), secret)
+	}
+	assert.Contains(t, string(cassette), Redacted)
+
+	// Replay without the server.
+	server.Close()
+	transport, err = CassetteTransport(path, nil)
+	require.Nil(t, err)
+	require.IsType(t, &Replayer{}, transport)
+	client = cassetteClient(server, transport)
+
+	replayed, _, err := client.GameServers.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, Redacted, replayed.Credentials.Ftp.Password)
+	assert.Equal(t, Redacted, replayed.Settings["general"]["rcon-password"])
+	assert.Equal(t, recorded.Slots, replayed.Slots)
+	require.Nil(t, client.GameServersSettings.Update(7654321, nitrado.GSSettingsUpdateOptions{Category: "general", Key: "rcon-password", Value: "other-secret"}))
+	require.Nil(t, client.GameServers.Restart(7654321))
+	replayedServices, _, err := client.Services.List()
+	require.Nil(t, err)
+	assert.Equal(t, len(*services), len(*replayedServices))
+	replayedData, err := fs.ReadFile(nitrado.FS(client, svc), "games/ni1_1/noftp/dayzxb/config/serverDZ.cfg")
+	require.Nil(t, err)
+	assert.Equal(t, data, replayedData)
+	assert.Equal(t, "admin log", download(transport))
+
+	// Repeated requests replay the last matching interaction.
+	_, _, err = client.GameServers.Get(7654321)
+	require.Nil(t, err)
+}
+
+// TestReplayer_RoundTrip tests matching requests in a Replayer.
+func TestReplayer_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.Nil(t, (&Cassette{Interactions: []Interaction{
+		{Request: CassetteRequest{Method: "GET", URL: "https://api.nitrado.net/services/1/gameservers/stats?hours=1"}, Response: CassetteResponse{StatusCode: 200, Body: "first"}},
+		{Request: CassetteRequest{Method: "GET", URL: "https://api.nitrado.net/services/1/gameservers/stats?hours=1"}, Response: CassetteResponse{StatusCode: 200, BodyBase64: "/w=="}},
+	}}).Save(path))
+	replayer, err := NewReplayer(path)
+	require.Nil(t, err)
+
+	roundTrip := func(method, u string) ([]byte, error) {
+		req, err := http.NewRequest(method, u, nil)
+		require.Nil(t, err)
+		resp, err := replayer.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		var buf [8]byte
+		n, _ := resp.Body.Read(buf[:])
+		return buf[:n], nil
+	}
+
+	// The host is ignored, as a cassette may be replayed against any BaseURI.
+	body, err := roundTrip("GET", "http://localhost/services/1/gameservers/stats?hours=1")
+	require.Nil(t, err)
+	assert.Equal(t, "first", string(body))
+	body, err = roundTrip("GET", "http://localhost/services/1/gameservers/stats?hours=1")
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0xff}, body)
+
+	_, err = roundTrip("GET", "http://localhost/services/1/gameservers/stats?hours=2")
+	assert.True(t, errors.Is(err, ErrNoInteraction))
+	_, err = roundTrip("POST", "http://localhost/services/1/gameservers/stats?hours=1")
+	assert.True(t, errors.Is(err, ErrNoInteraction))
+}
+
+// TestScrubJSON tests the scrubJSON() function.
+func TestScrubJSON(t *testing.T) {
+	got := scrubJSON([]byte(`{"data":{"token":{"url":"https://files/x","token":"abc"},"ftp":{"password":"p","port":21},"list":[{"secret_key":"s"}],"password":""}}`), DefaultScrubKeys)
+	assert.JSONEq(t, `{"data":{"token":{"url":"https://files/x","token":"REDACTED"},"ftp":{"password":"REDACTED","port":21},"list":[{"secret_key":"REDACTED"}],"password":""}}`, string(got))
+
+	got = scrubJSON([]byte(`{"data":{"token":{"url":"https://files.example/download/?token=abc&file=a.ADM"},"links":["https://files.example/x?password=p"],"site":"https://example.com/?q=1"}}`), DefaultScrubKeys)
+	assert.JSONEq(t, `{"data":{"token":{"url":"https://files.example/download/?file=a.ADM&token=REDACTED"},"links":["https://files.example/x?password=REDACTED"],"site":"https://example.com/?q=1"}}`, string(got))
+
+	assert.Equal(t, "not json", string(scrubJSON([]byte("not json"), DefaultScrubKeys)))
+	assert.Equal(t, `{"a":1.50}`, string(scrubJSON([]byte(`{"a":1.50}`), DefaultScrubKeys)))
+}
@@ -0,0 +1,197 @@
+package nitradotest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// transfer is a pending download or upload, identified by its token.
+type transfer struct {
+	serviceID int
+	path      string
+	upload    bool
+}
+
+// listFiles serves the entries of a directory, optionally only those whose
+// name contains search.
+func (s *Server) listFiles(w http.ResponseWriter, svc *service, dir, search string) {
+	dir = cleanPath(dir)
+	if f, ok := svc.files[dir]; !ok || !f.dir {
+		writeError(w, http.StatusNotFound, errNotFound(dir))
+		return
+	}
+
+	entries := []nitrado.File{}
+	for p, f := range svc.files {
+		if p == "/" || path.Dir(p) != dir {
+			continue
+		}
+		name := path.Base(p)
+		if search != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(search)) {
+			continue
+		}
+		entry := nitrado.File{
+			Path:       p,
+			Name:       name,
+			Type:       nitrado.FileTypeFile,
+			Size:       len(f.data),
+			ModifiedAt: int(f.modified.Unix()),
+			CreatedAt:  int(f.modified.Unix()),
+			AccessedAt: int(f.modified.Unix()),
+			Chmod:      "644",
+		}
+		if f.dir {
+			entry.Type = nitrado.FileTypeDir
+			entry.Chmod = "755"
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	writeData(w, map[string]interface{}{"entries": entries})
+}
+
+// download responds with a link to download a file.
+func (s *Server) download(w http.ResponseWriter, svc *service, name string) {
+	p := cleanPath(name)
+	if f, ok := svc.files[p]; !ok || f.dir {
+		writeError(w, http.StatusNotFound, errNotFound(p))
+		return
+	}
+	token := s.newTransfer(transfer{serviceID: svc.svc.ID, path: p})
+	writeData(w, map[string]interface{}{"token": map[string]string{"url": s.server.URL + "/_files/" + token, "token": token}})
+}
+
+// upload responds with a link and token to upload a file to a directory.
+func (s *Server) upload(w http.ResponseWriter, svc *service, dir, name string) {
+	dir = cleanPath(dir)
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid file name")
+		return
+	}
+	if f, ok := svc.files[dir]; !ok || !f.dir {
+		writeError(w, http.StatusNotFound, errNotFound(dir))
+		return
+	}
+	token := s.newTransfer(transfer{serviceID: svc.svc.ID, path: path.Join(dir, name), upload: true})
+	writeData(w, map[string]interface{}{"token": map[string]string{"url": s.server.URL + "/_files/" + token, "token": token}})
+}
+
+// deleteFile deletes a file, or a directory and its contents.
+func (s *Server) deleteFile(w http.ResponseWriter, svc *service, name string) {
+	p := cleanPath(name)
+	if _, ok := svc.files[p]; !ok || p == "/" {
+		writeError(w, http.StatusNotFound, errNotFound(p))
+		return
+	}
+	for other := range svc.files {
+		if other == p || strings.HasPrefix(other, p+"/") {
+			delete(svc.files, other)
+		}
+	}
+	writeMessage(w, "File has been deleted.")
+}
+
+// mkdir creates the directory name in dir.
+func (s *Server) mkdir(w http.ResponseWriter, svc *service, dir, name string) {
+	dir = cleanPath(dir)
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid directory name")
+		return
+	}
+	if f, ok := svc.files[dir]; !ok || !f.dir {
+		writeError(w, http.StatusNotFound, errNotFound(dir))
+		return
+	}
+	p := path.Join(dir, name)
+	if _, ok := svc.files[p]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("%q already exists", p))
+		return
+	}
+	svc.files[p] = &file{dir: true, modified: s.Now()}
+	writeMessage(w, "Directory has been created.")
+}
+
+// newTransfer stores t and returns its token. The caller holds s.mu.
+func (s *Server) newTransfer(t transfer) string {
+	if s.transfers == nil {
+		s.transfers = make(map[string]transfer)
+	}
+	s.nextToken++
+	token := "transfer-" + strconv.Itoa(s.nextToken)
+	s.transfers[token] = t
+	return token
+}
+
+// serveFileTransfer serves the download and upload links, which are
+// authenticated by their token rather than the API token.
+func (s *Server) serveFileTransfer(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, "/_files/")
+
+	s.mu.Lock()
+	t, ok := s.transfers[token]
+	var svc *service
+	if ok {
+		svc, ok = s.services[t.serviceID]
+	}
+	if !ok {
+		s.mu.Unlock()
+		http.Error(w, "invalid token", http.StatusForbidden)
+		return
+	}
+
+	if !t.upload {
+		if r.Method != "GET" {
+			s.mu.Unlock()
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		f, ok := svc.files[t.path]
+		if !ok || f.dir {
+			s.mu.Unlock()
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		data, modified := f.data, f.modified
+		s.mu.Unlock()
+		http.ServeContent(w, r, path.Base(t.path), modified, bytes.NewReader(data))
+		return
+	}
+	s.mu.Unlock()
+
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("token") != token {
+		http.Error(w, "invalid token", http.StatusForbidden)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.transfers, token)
+	svc.writeFile(t.path, data, s.Now())
+	writeMessage(w, "File has been uploaded.")
+}
+
+// parsePositive parses a positive integer.
+func parsePositive(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err == nil && n <= 0 {
+		err = fmt.Errorf("%d is not positive", n)
+	}
+	return n, err
+}
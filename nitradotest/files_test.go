@@ -0,0 +1,73 @@
+package nitradotest
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServer_Files tests listing, downloading and deleting files.
+func TestServer_Files(t *testing.T) {
+	s := newServer(t)
+	s.WriteFile(7654321, "/games/ni1_1/noftp/dayzxb/config/serverDZ.cfg", []byte(`hostname = "My Server";`))
+	s.MkdirAll(7654321, "/games/ni1_1/noftp/dayzxb/mpmissions")
+	s.SetBookmarks(7654321, "/games/ni1_1/noftp/dayzxb/config")
+	client := s.Client()
+	svc := nitrado.Service{ID: 7654321}
+
+	files, _, err := client.FileServerService.List(svc, nitrado.FileServerListOptions{Dir: "/games/ni1_1/noftp/dayzxb", SortBy: nitrado.FileSortName})
+	require.Nil(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "config", files[0].Name)
+	assert.Equal(t, nitrado.FileTypeDir, files[0].Type)
+	files, _, err = client.FileServerService.List(svc, nitrado.FileServerListOptions{Dir: "/games/ni1_1/noftp/dayzxb/config", Search: "DZ"})
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, 23, files[0].Size)
+
+	dir, _, err := client.FileServerService.ResolveBookmark(svc, "config")
+	require.Nil(t, err)
+	data, err := fs.ReadFile(nitrado.FS(client, svc), dir[1:]+"/serverDZ.cfg")
+	require.Nil(t, err)
+	assert.Equal(t, `hostname = "My Server";`, string(data))
+
+	_, err = client.FileServerService.Mkdir(svc, nitrado.FileServerMkdirOptions{Path: "/games/ni1_1/noftp/dayzxb", Name: "logs"})
+	require.Nil(t, err)
+	_, err = client.FileServerService.Delete(svc, nitrado.FileServerDeleteOptions{Path: "/games/ni1_1/noftp/dayzxb/config"})
+	require.Nil(t, err)
+	_, ok := s.ReadFile(7654321, "/games/ni1_1/noftp/dayzxb/config/serverDZ.cfg")
+	assert.False(t, ok)
+	files, _, err = client.FileServerService.List(svc, nitrado.FileServerListOptions{Dir: "/games/ni1_1/noftp/dayzxb", SortBy: nitrado.FileSortName})
+	require.Nil(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "logs", files[0].Name)
+
+	assert.Equal(t, http.StatusNotFound, get(t, s, "GET", "services/7654321/gameservers/file_server/list?dir=/missing", DefaultToken).StatusCode)
+	assert.Equal(t, http.StatusNotFound, get(t, s, "GET", "services/7654321/gameservers/file_server/download?file=/missing", DefaultToken).StatusCode)
+	assert.Equal(t, http.StatusForbidden, get(t, s, "GET", "_files/guessed", "").StatusCode)
+}
+
+// TestServer_Upload tests uploading files with a sync.
+func TestServer_Upload(t *testing.T) {
+	s := newServer(t)
+	s.MkdirAll(7654321, "/games/ni1_1/noftp/dayzxb/mpmissions")
+	local := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(local, "dayzOffline.chernarusplus"), 0o755))
+	require.Nil(t, os.WriteFile(filepath.Join(local, "dayzOffline.chernarusplus", "init.c"), []byte("void main() {}"), 0o644))
+
+	_, err := s.Client().Sync.Run(nitrado.Service{ID: 7654321}, nitrado.SyncOptions{
+		Direction: nitrado.SyncPush,
+		LocalDir:  local,
+		RemoteDir: "/games/ni1_1/noftp/dayzxb/mpmissions",
+	})
+	require.Nil(t, err)
+	data, ok := s.ReadFile(7654321, "/games/ni1_1/noftp/dayzxb/mpmissions/dayzOffline.chernarusplus/init.c")
+	require.True(t, ok)
+	assert.Equal(t, "void main() {}", string(data))
+}
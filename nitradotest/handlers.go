@@ -0,0 +1,222 @@
+package nitradotest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// serveGameServer serves the endpoints below services/{id}/gameservers.
+// The caller holds s.mu.
+func (s *Server) serveGameServer(w http.ResponseWriter, r *http.Request, svc *service, endpoint string) {
+	method := map[string]string{
+		"":                      "GET",
+		"restart":               "POST",
+		"stats":                 "GET",
+		"settings":              "POST",
+		"settings/schema":       "GET",
+		"app_server/command":    "POST",
+		"games/players":         "GET",
+		"games/players/kick":    "POST",
+		"file_server/list":      "GET",
+		"file_server/bookmarks": "GET",
+		"file_server/download":  "GET",
+		"file_server/upload":    "POST",
+		"file_server/delete":    "DELETE",
+		"file_server/mkdir":     "POST",
+	}
+	switch endpoint {
+	case "games/banlist", "games/whitelist", "games/adminlist":
+		if r.Method != "PUT" && r.Method != "DELETE" {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		s.listAction(w, r, svc, strings.TrimPrefix(endpoint, "games/"))
+		return
+	}
+	want, ok := method[endpoint]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if r.Method != want {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	switch endpoint {
+	case "":
+		writeData(w, map[string]interface{}{"gameserver": s.currentGameServer(svc)})
+	case "restart":
+		s.restart(w, svc)
+	case "stats":
+		s.serveStats(w, r, svc)
+	case "settings":
+		s.updateSetting(w, svc, q.Get("category"), q.Get("key"), q.Get("value"))
+	case "settings/schema":
+		if svc.schema == nil {
+			writeError(w, http.StatusNotFound, "No settings schema for this game")
+			return
+		}
+		settings := make(map[string]map[string]nitrado.SettingDefinition)
+		for _, d := range svc.schema.Definitions() {
+			if settings[d.Category] == nil {
+				settings[d.Category] = make(map[string]nitrado.SettingDefinition)
+			}
+			settings[d.Category][d.Key] = d
+		}
+		writeData(w, map[string]interface{}{"settings": settings})
+	case "app_server/command":
+		s.command(w, svc, q.Get("command"))
+	case "games/players":
+		players := svc.players
+		if players == nil {
+			players = []nitrado.Player{}
+		}
+		writeData(w, map[string]interface{}{"players": players})
+	case "games/players/kick":
+		s.kick(w, svc, q.Get("identifier"))
+	case "file_server/list":
+		s.listFiles(w, svc, q.Get("dir"), q.Get("search"))
+	case "file_server/bookmarks":
+		bookmarks := svc.bookmarks
+		if bookmarks == nil {
+			bookmarks = []string{}
+		}
+		writeData(w, map[string]interface{}{"bookmarks": bookmarks})
+	case "file_server/download":
+		s.download(w, svc, q.Get("file"))
+	case "file_server/upload":
+		s.upload(w, svc, q.Get("path"), q.Get("file"))
+	case "file_server/delete":
+		s.deleteFile(w, svc, q.Get("path"))
+	case "file_server/mkdir":
+		s.mkdir(w, svc, q.Get("path"), q.Get("name"))
+	}
+}
+
+// restart restarts a game server, disconnecting its players.
+func (s *Server) restart(w http.ResponseWriter, svc *service) {
+	now := s.Now()
+	svc.restarts++
+	svc.restarted = now.Add(s.RestartDuration)
+	svc.gs.Status = "restarting"
+	svc.gs.LastStatusChange = int(now.Unix())
+	for i := range svc.players {
+		svc.players[i].Online = false
+	}
+	s.currentGameServer(svc)
+	writeMessage(w, "Server will be restarted now.")
+}
+
+// serveStats serves the points of the last hours of stats, 24 by default.
+func (s *Server) serveStats(w http.ResponseWriter, r *http.Request, svc *service) {
+	hours := 24
+	if h := r.URL.Query().Get("hours"); h != "" {
+		n, err := parsePositive(h)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid hours")
+			return
+		}
+		hours = n
+	}
+	now := s.Now()
+	from := now.Add(-time.Duration(hours) * time.Hour)
+	within := func(ts nitrado.TimeSeries) nitrado.TimeSeries {
+		series := ts.Between(from, now.Add(1))
+		if series == nil {
+			series = nitrado.TimeSeries{}
+		}
+		return series
+	}
+	writeData(w, map[string]interface{}{"stats": nitrado.GSStats{
+		CPUUsage:       within(svc.stats.CPUUsage),
+		CurrentPlayers: within(svc.stats.CurrentPlayers),
+		MaxPlayers:     within(svc.stats.MaxPlayers),
+		MemoryUsage:    within(svc.stats.MemoryUsage),
+	}})
+}
+
+// updateSetting sets a setting, validating it against the schema if there
+// is one.
+func (s *Server) updateSetting(w http.ResponseWriter, svc *service, category, key, value string) {
+	if category == "" || key == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Category and key are required")
+		return
+	}
+	if svc.schema != nil {
+		if err := svc.schema.Validate(category, key, value); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	}
+	svc.gs.Settings.Set(category, key, value)
+	writeMessage(w, "Setting has been updated.")
+}
+
+// command records a console command and responds with its output.
+func (s *Server) command(w http.ResponseWriter, svc *service, command string) {
+	if strings.TrimSpace(command) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Command is required")
+		return
+	}
+	svc.commands = append(svc.commands, command)
+	var output string
+	if s.Console != nil {
+		output = s.Console(svc.svc.ID, command)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Command has been sent",
+		"data":    map[string]string{"output": output},
+	})
+}
+
+// kick disconnects an online player.
+func (s *Server) kick(w http.ResponseWriter, svc *service, id string) {
+	for i, p := range svc.players {
+		if p.ID == id && p.Online {
+			svc.players[i].Online = false
+			writeMessage(w, "Player has been kicked.")
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Player is not online")
+}
+
+// listAction adds or removes a player on the ban list, whitelist or admin
+// list. The ban list and whitelist are kept in the general settings, like
+// the Nitrado API does.
+func (s *Server) listAction(w http.ResponseWriter, r *http.Request, svc *service, list string) {
+	id := r.URL.Query().Get("identifier")
+	if id == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Identifier is required")
+		return
+	}
+
+	key := map[string]string{"banlist": "bans", "whitelist": "whitelist", "adminlist": "admins"}[list]
+	current, _ := svc.gs.Settings.Value("general", key)
+	var entries []string
+	found := false
+	for _, e := range strings.Split(current, "\n") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if e == id {
+			found = true
+			if r.Method == "DELETE" {
+				continue
+			}
+		}
+		entries = append(entries, e)
+	}
+	if r.Method == "PUT" && !found {
+		entries = append(entries, id)
+	}
+	svc.gs.Settings.Set("general", key, strings.Join(entries, "\r\n"))
+	writeMessage(w, "Player list has been updated.")
+}
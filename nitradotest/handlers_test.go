@@ -0,0 +1,133 @@
+package nitradotest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServer_Restart tests restarting a game server.
+func TestServer_Restart(t *testing.T) {
+	s := newServer(t)
+	now := time.Unix(1622359920, 0)
+	s.Now = func() time.Time { return now }
+	s.RestartDuration = time.Minute
+	s.SetPlayers(7654321, nitrado.Player{Name: "Survivor", ID: "1", Online: true})
+	client := s.Client()
+
+	gs, _, err := client.GameServers.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, 1, gs.Query.PlayerCurrent)
+
+	require.Nil(t, client.GameServers.Restart(7654321))
+	gs, _, err = client.GameServers.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, "restarting", gs.Status)
+	assert.Equal(t, 0, gs.Query.PlayerCurrent)
+	assert.False(t, s.Players(7654321)[0].Online)
+
+	now = now.Add(time.Minute)
+	current, ok := s.GameServer(7654321)
+	require.True(t, ok)
+	assert.Equal(t, "started", current.Status)
+	assert.Equal(t, int(now.Unix()), current.LastStatusChange)
+	assert.Equal(t, 1, s.Restarts(7654321))
+}
+
+// TestServer_Settings tests updating the settings of a game server.
+func TestServer_Settings(t *testing.T) {
+	s := newServer(t)
+	s.SetSetting(7654321, "config", "hostname", "My Server")
+	client := s.Client()
+
+	settings, _, err := client.GameServersSettings.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, "My Server", settings["config"]["hostname"])
+
+	require.Nil(t, client.GameServersSettings.Update(7654321, nitrado.GSSettingsUpdateOptions{Category: "config", Key: "hostname", Value: "Renamed"}))
+	gs, _ := s.GameServer(7654321)
+	assert.Equal(t, "Renamed", gs.Settings["config"]["hostname"])
+
+	// With a schema, the server rejects invalid settings.
+	s.SetSchema(7654321, nitrado.SettingsSchema{
+		"config": {"hostname": {Category: "config", Key: "hostname", Type: nitrado.SettingText}},
+	})
+	schema, _, err := client.GameServersSettings.Schema(7654321)
+	require.Nil(t, err)
+	_, ok := schema.Lookup("config", "hostname")
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, get(t, s, "POST", "services/7654321/gameservers/settings?category=config&key=unknown&value=1", DefaultToken).StatusCode)
+}
+
+// TestServer_Players tests the player list and player actions.
+func TestServer_Players(t *testing.T) {
+	s := newServer(t)
+	all := []string{"kick", "ban", "unban", "whitelist", "unwhitelist"}
+	survivor := nitrado.Player{Name: "Survivor", ID: "1", Online: true, Actions: all}
+	s.SetPlayers(7654321, survivor, nitrado.Player{Name: "Bandit", ID: "2", Actions: all})
+	client := s.Client()
+	svc := nitrado.Service{ID: 7654321}
+
+	players, _, err := client.PlayerListService.List(svc)
+	require.Nil(t, err)
+	require.Len(t, players, 2)
+	assert.Equal(t, "Bandit", players[0].Name)
+
+	_, err = client.PlayerListService.Kick(svc, survivor)
+	require.Nil(t, err)
+	assert.False(t, s.Players(7654321)[0].Online)
+
+	_, err = client.PlayerListService.Ban(svc, survivor)
+	require.Nil(t, err)
+	_, err = client.PlayerListService.Ban(svc, players[0])
+	require.Nil(t, err)
+	bans, _, err := client.AccessLists.Bans(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, bans.Entries())
+
+	_, err = client.PlayerListService.Unban(svc, survivor)
+	require.Nil(t, err)
+	bans, _, err = client.AccessLists.Bans(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"2"}, bans.Entries())
+}
+
+// TestServer_Stats tests serving the stats of a game server.
+func TestServer_Stats(t *testing.T) {
+	s := newServer(t)
+	now := time.Unix(1622359920, 0)
+	s.Now = func() time.Time { return now }
+	s.SetStats(7654321, nitrado.GSStats{CurrentPlayers: nitrado.TimeSeries{
+		{Time: now.Add(-30 * time.Hour), Value: 1},
+		{Time: now.Add(-2 * time.Hour), Value: 2},
+		{Time: now.Add(-time.Minute), Value: 3},
+	}})
+	client := s.Client()
+
+	stats, _, err := client.GameServerStats.Get(7654321, nitrado.GSStatsOptions{})
+	require.Nil(t, err)
+	assert.Len(t, stats.CurrentPlayers, 2)
+	stats, _, err = client.GameServerStats.Get(7654321, nitrado.GSStatsOptions{Hours: 1})
+	require.Nil(t, err)
+	require.Len(t, stats.CurrentPlayers, 1)
+	assert.Equal(t, 3.0, stats.CurrentPlayers[0].Value)
+	assert.NotNil(t, stats.CPUUsage)
+}
+
+// TestServer_Console tests sending console commands.
+func TestServer_Console(t *testing.T) {
+	s := newServer(t)
+	s.Console = func(serviceID int, command string) string {
+		return strings.ToUpper(command)
+	}
+
+	result, _, err := s.Client().Console.Send(7654321, "say hello")
+	require.Nil(t, err)
+	assert.Equal(t, "SAY HELLO", result.Output)
+	assert.Equal(t, []string{"say hello"}, s.Commands(7654321))
+}
@@ -0,0 +1,272 @@
+// Package nitradotest provides an in-memory fake of the Nitrado API for
+// testing code which uses the nitrado package.
+//
+// A Server is seeded with services, game servers, files, players, settings
+// and stats, and then serves them like the Nitrado API. Requests which
+// change state, such as restarts, settings updates and file operations,
+// change the seeded state, which tests can inspect afterwards. Faults such
+// as errors, latency and rate limiting can be injected with Inject.
+//
+// The nitrado client retries network errors and responses with a status of
+// 429 or 500 and above. The client returned by Server.Client retries without
+// delay, so injected faults, such as RateLimit, do not slow tests down. A
+// Fault with any other Status returns an error message which the client
+// reports without retrying.
+package nitradotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// DefaultToken is the API token accepted by a new Server.
+const DefaultToken = "nitradotest-token"
+
+// Fault makes the Server fail, or delay, matching requests.
+type Fault struct {
+	Method string // Only match requests with this method, if set.
+	Path   string // Only match requests whose path matches this path.Match pattern, if set, such as "/services/*/gameservers/restart".
+
+	// Status is the HTTP status to respond with, with an error message. A
+	// Status of 0 serves the request normally, after Delay.
+	Status  int
+	Message string // The error message, defaults to the text of Status.
+
+	Delay time.Duration // How long to wait before responding.
+	Times int           // How many requests to match, or 0 for every request until ClearFaults.
+}
+
+// Server is a fake Nitrado API. Create it with NewServer.
+type Server struct {
+	server *httptest.Server
+
+	// Token is the API token which requests must use, defaults to
+	// DefaultToken. When empty, any token is accepted.
+	Token string
+
+	// Now returns the current time, defaults to time.Now. Stats are
+	// filtered, and restarts complete, relative to it.
+	Now func() time.Time
+
+	// RestartDuration is how long a game server stays "restarting" after a
+	// restart before it is "started" again. When zero, restarts complete
+	// immediately.
+	RestartDuration time.Duration
+
+	// Console returns the output of a console command. When nil, commands
+	// return no output.
+	Console func(serviceID int, command string) string
+
+	mu        sync.Mutex
+	services  map[int]*service
+	faults    []*Fault
+	requests  []string
+	transfers map[string]transfer // Pending downloads and uploads, by token.
+	nextToken int
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Token:    DefaultToken,
+		Now:      time.Now,
+		services: make(map[int]*service),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the Server, with a trailing slash.
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+// Close shuts down the Server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a nitrado.Client for the Server, using its Token. The
+// client retries failed requests without delay.
+func (s *Server) Client() *nitrado.Client {
+	c := nitrado.NewClient(s.Token)
+	c.BaseURI, _ = url.Parse(s.URL())
+	c.RetryDelay = 0
+	return c
+}
+
+// Inject adds a fault. Faults are matched in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// RateLimit makes the next n requests fail with 429 Too Many Requests.
+func (s *Server) RateLimit(n int) {
+	s.Inject(Fault{Status: http.StatusTooManyRequests, Message: "Rate limit exceeded", Times: n})
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests served so far, as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// fault returns the first fault matching r, counting it against its Times.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, r.URL.Path); !ok {
+				continue
+			}
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.mu.Unlock()
+
+	if f := s.fault(r); f != nil {
+		if f.Delay > 0 {
+			select {
+			case <-time.After(f.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if f.Status != 0 {
+			msg := f.Message
+			if msg == "" {
+				msg = http.StatusText(f.Status)
+			}
+			if f.Status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, f.Status, msg)
+			return
+		}
+	}
+
+	// Downloads and uploads use the token in their URL, like the Nitrado
+	// file server.
+	if strings.HasPrefix(r.URL.Path, "/_files/") {
+		s.serveFileTransfer(w, r)
+		return
+	}
+
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "Invalid access token")
+		return
+	}
+
+	elems := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if elems[0] != "services" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if len(elems) == 1 {
+		s.listServices(w, r)
+		return
+	}
+	id, err := strconv.Atoi(elems[1])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Service not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, ok := s.services[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Service not found")
+		return
+	}
+	if len(elems) == 2 {
+		writeData(w, map[string]interface{}{"service": svc.svc})
+		return
+	}
+	if elems[2] != "gameservers" || svc.gs == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	s.serveGameServer(w, r, svc, strings.Join(elems[3:], "/"))
+}
+
+// listServices serves the services, ordered by ID.
+func (s *Server) listServices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int, 0, len(s.services))
+	for id := range s.services {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	services := make([]nitrado.Service, 0, len(ids))
+	for _, id := range ids {
+		services = append(services, s.services[id].svc)
+	}
+	writeData(w, map[string]interface{}{"services": services})
+}
+
+// writeData writes a successful response with data.
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": data})
+}
+
+// writeMessage writes a successful response with a message and no data.
+func writeMessage(w http.ResponseWriter, msg string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "message": msg})
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{"status": "error", "message": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// errNotFound formats the message for a missing file or directory.
+func errNotFound(p string) string {
+	return fmt.Sprintf("File or directory %q not found", p)
+}
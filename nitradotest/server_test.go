@@ -0,0 +1,113 @@
+package nitradotest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a Server seeded with a game server and a web space.
+func newServer(t *testing.T) *Server {
+	s := NewServer()
+	t.Cleanup(s.Close)
+
+	svc := nitrado.Service{ID: 7654321, SuspendingIn: 86400}
+	svc.Details.Name = "My Server"
+	s.AddGameServer(svc, nitrado.GameServer{Game: "dayzxb", Slots: 10, MemoryMb: 4096})
+	s.AddService(nitrado.Service{ID: 1234567, Type: "webspace"})
+	return s
+}
+
+// get makes a raw request to the Server, avoiding the retries of the
+// nitrado client on error responses.
+func get(t *testing.T, s *Server, method, path, token string) *http.Response {
+	req, err := http.NewRequest(method, s.URL()+path, nil)
+	require.Nil(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+// TestServer_Services tests serving the seeded services.
+func TestServer_Services(t *testing.T) {
+	s := newServer(t)
+	client := s.Client()
+
+	services, _, err := client.Services.List()
+	require.Nil(t, err)
+	require.Len(t, *services, 2)
+	assert.Equal(t, 1234567, (*services)[0].ID)
+	assert.Equal(t, "gameserver", (*services)[1].Type)
+	assert.Equal(t, "active", (*services)[1].Status)
+	assert.Equal(t, "My Server", (*services)[1].Details.Name)
+
+	svc, _, err := client.Services.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, 86400, svc.SuspendingIn)
+
+	gs, _, err := client.GameServers.Get(7654321)
+	require.Nil(t, err)
+	assert.Equal(t, "started", gs.Status)
+	assert.Equal(t, 7654321, gs.ServiceID)
+	assert.Equal(t, 10, gs.Slots)
+
+	assert.Equal(t, http.StatusUnauthorized, get(t, s, "GET", "services", "wrong").StatusCode)
+	assert.Equal(t, http.StatusNotFound, get(t, s, "GET", "services/1", DefaultToken).StatusCode)
+	assert.Equal(t, http.StatusNotFound, get(t, s, "GET", "services/1234567/gameservers", DefaultToken).StatusCode)
+	assert.Equal(t, http.StatusMethodNotAllowed, get(t, s, "GET", "services/7654321/gameservers/restart", DefaultToken).StatusCode)
+
+	assert.Equal(t, []string{
+		"GET /services",
+		"GET /services/7654321",
+		"GET /services/7654321/gameservers",
+		"GET /services",
+		"GET /services/1",
+		"GET /services/1234567/gameservers",
+		"GET /services/7654321/gameservers/restart",
+	}, s.Requests())
+}
+
+// TestServer_Inject tests injecting faults.
+func TestServer_Inject(t *testing.T) {
+	s := newServer(t)
+
+	s.RateLimit(2)
+	resp := get(t, s, "GET", "services", DefaultToken)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, get(t, s, "GET", "services", DefaultToken).StatusCode)
+	assert.Equal(t, http.StatusOK, get(t, s, "GET", "services", DefaultToken).StatusCode)
+
+	// The client retries until the rate limit is over.
+	s.RateLimit(2)
+	_, _, err := s.Client().Services.List()
+	require.Nil(t, err)
+
+	s.Inject(Fault{Method: "GET", Path: "/services/*/gameservers", Status: http.StatusServiceUnavailable, Message: "Maintenance"})
+	_, _, err = s.Client().GameServers.Get(7654321)
+	assert.EqualError(t, err, `status "error" ("Maintenance")`)
+	s.ClearFaults()
+
+	s.Inject(Fault{Method: "POST", Path: "/services/*/gameservers/restart", Status: http.StatusOK, Message: "Server is locked"})
+	err = s.Client().GameServers.Restart(7654321)
+	assert.EqualError(t, err, `status "error" ("Server is locked")`)
+	assert.Equal(t, 0, s.Restarts(7654321))
+	assert.Equal(t, http.StatusOK, get(t, s, "GET", "services/7654321/gameservers", DefaultToken).StatusCode)
+
+	s.ClearFaults()
+	s.Inject(Fault{Delay: 50 * time.Millisecond, Times: 1})
+	start := time.Now()
+	_, _, err = s.Client().Services.List()
+	require.Nil(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	require.Nil(t, s.Client().GameServers.Restart(7654321))
+	assert.Equal(t, 1, s.Restarts(7654321))
+}
@@ -0,0 +1,240 @@
+package nitradotest
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+// service is the state of a seeded service.
+type service struct {
+	svc       nitrado.Service
+	gs        *nitrado.GameServer // Nil for services which are not game servers.
+	restarted time.Time           // When the current restart completes.
+
+	players   []nitrado.Player
+	stats     nitrado.GSStats
+	schema    nitrado.SettingsSchema
+	files     map[string]*file // By absolute path.
+	bookmarks []string
+	commands  []string
+	restarts  int
+}
+
+// file is a file or directory on the file server of a game server.
+type file struct {
+	dir      bool
+	data     []byte
+	modified time.Time
+}
+
+// AddService seeds a service which is not a game server, such as a web
+// space. An existing service with the same ID is replaced.
+func (s *Server) AddService(svc nitrado.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services[svc.ID] = &service{svc: svc, files: map[string]*file{"/": {dir: true}}}
+}
+
+// AddGameServer seeds a game server service. The type of svc defaults to
+// "gameserver" and its status to "active", and the status of gs defaults to
+// "started". An existing service with the same ID is replaced.
+func (s *Server) AddGameServer(svc nitrado.Service, gs nitrado.GameServer) {
+	if svc.Type == "" {
+		svc.Type = "gameserver"
+	}
+	if svc.Status == "" {
+		svc.Status = "active"
+	}
+	gs.ServiceID = svc.ID
+	if gs.Status == "" {
+		gs.Status = "started"
+	}
+	gs.Settings = copySettings(gs.Settings)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services[svc.ID] = &service{svc: svc, gs: &gs, files: map[string]*file{"/": {dir: true}}}
+}
+
+// SetPlayers replaces the players of a game server.
+func (s *Server) SetPlayers(serviceID int, players ...nitrado.Player) {
+	s.update(serviceID, true, func(svc *service) {
+		svc.players = append([]nitrado.Player(nil), players...)
+	})
+}
+
+// SetStats replaces the stats of a game server.
+func (s *Server) SetStats(serviceID int, stats nitrado.GSStats) {
+	s.update(serviceID, true, func(svc *service) {
+		svc.stats = stats
+	})
+}
+
+// SetSetting sets a setting of a game server.
+func (s *Server) SetSetting(serviceID int, category, key, value string) {
+	s.update(serviceID, true, func(svc *service) {
+		svc.gs.Settings.Set(category, key, value)
+	})
+}
+
+// SetSchema sets the settings schema of a game server. Settings updates
+// are validated against it, and it is served by the schema endpoint.
+func (s *Server) SetSchema(serviceID int, schema nitrado.SettingsSchema) {
+	s.update(serviceID, true, func(svc *service) {
+		svc.schema = schema
+	})
+}
+
+// SetBookmarks sets the bookmarked directories of a game server.
+func (s *Server) SetBookmarks(serviceID int, bookmarks ...string) {
+	s.update(serviceID, true, func(svc *service) {
+		svc.bookmarks = append([]string(nil), bookmarks...)
+	})
+}
+
+// WriteFile creates or replaces a file on the file server of a service,
+// creating its parent directories.
+func (s *Server) WriteFile(serviceID int, name string, data []byte) {
+	s.update(serviceID, false, func(svc *service) {
+		svc.writeFile(cleanPath(name), data, s.Now())
+	})
+}
+
+// MkdirAll creates a directory and its parents on the file server of a
+// service.
+func (s *Server) MkdirAll(serviceID int, dir string) {
+	s.update(serviceID, false, func(svc *service) {
+		svc.mkdirAll(cleanPath(dir), s.Now())
+	})
+}
+
+// GameServer returns the current state of a game server, and false when
+// there is none.
+func (s *Server) GameServer(serviceID int) (nitrado.GameServer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, ok := s.services[serviceID]
+	if !ok || svc.gs == nil {
+		return nitrado.GameServer{}, false
+	}
+	gs := s.currentGameServer(svc)
+	gs.Settings = copySettings(gs.Settings)
+	return gs, true
+}
+
+// Players returns the current players of a game server.
+func (s *Server) Players(serviceID int) []nitrado.Player {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if svc, ok := s.services[serviceID]; ok {
+		return append([]nitrado.Player(nil), svc.players...)
+	}
+	return nil
+}
+
+// ReadFile returns the contents of a file on the file server of a service,
+// and false when it does not exist.
+func (s *Server) ReadFile(serviceID int, name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, ok := s.services[serviceID]
+	if !ok {
+		return nil, false
+	}
+	f, ok := svc.files[cleanPath(name)]
+	if !ok || f.dir {
+		return nil, false
+	}
+	return append([]byte(nil), f.data...), true
+}
+
+// Commands returns the console commands sent to a game server.
+func (s *Server) Commands(serviceID int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if svc, ok := s.services[serviceID]; ok {
+		return append([]string(nil), svc.commands...)
+	}
+	return nil
+}
+
+// Restarts returns how often a game server has been restarted.
+func (s *Server) Restarts(serviceID int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if svc, ok := s.services[serviceID]; ok {
+		return svc.restarts
+	}
+	return 0
+}
+
+// update calls fn with a seeded service, which must be a game server when
+// gameServer is set. It panics when there is no such service, as that is a
+// mistake in the test.
+func (s *Server) update(serviceID int, gameServer bool, fn func(svc *service)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, ok := s.services[serviceID]
+	if !ok {
+		panic(fmt.Sprintf("nitradotest: no service with ID %d", serviceID))
+	}
+	if gameServer && svc.gs == nil {
+		panic(fmt.Sprintf("nitradotest: service %d is not a game server", serviceID))
+	}
+	fn(svc)
+}
+
+// currentGameServer returns the game server of svc, completing a pending
+// restart and counting the players online.
+func (s *Server) currentGameServer(svc *service) nitrado.GameServer {
+	if svc.gs.Status == "restarting" && !s.Now().Before(svc.restarted) {
+		svc.gs.Status = "started"
+		svc.gs.LastStatusChange = int(svc.restarted.Unix())
+	}
+	online := 0
+	for _, p := range svc.players {
+		if p.Online {
+			online++
+		}
+	}
+	gs := *svc.gs
+	gs.Query.PlayerCurrent = online
+	return gs
+}
+
+func (svc *service) writeFile(p string, data []byte, now time.Time) {
+	svc.mkdirAll(path.Dir(p), now)
+	svc.files[p] = &file{data: append([]byte(nil), data...), modified: now}
+}
+
+func (svc *service) mkdirAll(dir string, now time.Time) {
+	for d := dir; ; d = path.Dir(d) {
+		if f, ok := svc.files[d]; ok && f.dir {
+			return
+		}
+		svc.files[d] = &file{dir: true, modified: now}
+		if d == "/" {
+			return
+		}
+	}
+}
+
+// cleanPath returns p as a clean absolute path.
+func cleanPath(p string) string {
+	return path.Clean("/" + strings.TrimSpace(p))
+}
+
+func copySettings(settings nitrado.Settings) nitrado.Settings {
+	c := make(nitrado.Settings, len(settings))
+	for cat, values := range settings {
+		c[cat] = make(map[string]string, len(values))
+		for k, v := range values {
+			c[cat][k] = v
+		}
+	}
+	return c
+}
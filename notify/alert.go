@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/danstis/go-nitrado/alert"
+)
+
+// Alert returns the message for an alert.
+func Alert(a alert.Alert) Message {
+	if a.Resolved {
+		return Message{Title: "Resolved: " + a.Rule, Color: ColorGreen, Time: a.Time}
+	}
+	return Message{Title: "Firing: " + a.Rule, Text: a.Message, Color: ColorRed, Time: a.Time}
+}
+
+// Alerts returns an alert.Notifier which sends alerts through n.
+func Alerts(n Notifier) alert.Notifier {
+	return alert.NotifierFunc(func(ctx context.Context, a alert.Alert) error {
+		return n.Notify(ctx, Alert(a))
+	})
+}
@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/alert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAlerts tests sending alerts through a Notifier.
+func TestAlerts(t *testing.T) {
+	var got []Message
+	n := Alerts(NotifierFunc(func(ctx context.Context, m Message) error {
+		got = append(got, m)
+		return nil
+	}))
+
+	now := time.Unix(1622359920, 0)
+	require.Nil(t, n.Notify(context.Background(), alert.Alert{Rule: "memory", Message: "memory_usage is 3900, above 3600", Time: now}))
+	require.Nil(t, n.Notify(context.Background(), alert.Alert{Rule: "memory", Resolved: true, Time: now}))
+	assert.Equal(t, []Message{
+		{Title: "Firing: memory", Text: "memory_usage is 3900, above 3600", Color: ColorRed, Time: now},
+		{Title: "Resolved: memory", Color: ColorGreen, Time: now},
+	}, got)
+}
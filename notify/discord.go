@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Limits of a Discord embed.
+const (
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+	discordFieldLimit       = 25
+	discordFieldNameLimit   = 256
+	discordFieldValueLimit  = 1024
+)
+
+// Discord is a Notifier which posts each Message as an embed to a Discord
+// webhook.
+type Discord struct {
+	URL       string // The webhook URL, from the integrations settings of a channel.
+	Username  string // Overrides the name of the webhook, if set.
+	AvatarURL string // Overrides the avatar of the webhook, if set.
+
+	Client *http.Client // The client to post with, defaults to http.DefaultClient.
+
+	Retries    int           // How often to retry a failed post, defaults to 3. Set a negative value to disable retries.
+	RetryDelay time.Duration // The delay before the first retry, defaults to 1 second, doubling with each retry. Rate limits use the delay asked for by Discord.
+}
+
+type discordPayload struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string  `json:"title,omitempty"`
+	Description string  `json:"description,omitempty"`
+	URL         string  `json:"url,omitempty"`
+	Color       int     `json:"color,omitempty"`
+	Fields      []Field `json:"fields,omitempty"`
+	Timestamp   string  `json:"timestamp,omitempty"`
+}
+
+// Notify posts the message as an embed, truncating it to the limits of
+// Discord. Rate limited posts are retried after the delay asked for by
+// Discord.
+func (d *Discord) Notify(ctx context.Context, m Message) error {
+	embed := discordEmbed{
+		Title:       truncate(m.Title, discordTitleLimit),
+		Description: truncate(m.Text, discordDescriptionLimit),
+		URL:         m.URL,
+		Color:       m.Color,
+	}
+	if !m.Time.IsZero() {
+		embed.Timestamp = m.Time.UTC().Format(time.RFC3339)
+	}
+	for i, f := range m.Fields {
+		if i == discordFieldLimit {
+			break
+		}
+		embed.Fields = append(embed.Fields, Field{
+			Name:   truncate(f.Name, discordFieldNameLimit),
+			Value:  truncate(f.Value, discordFieldValueLimit),
+			Inline: f.Inline,
+		})
+	}
+
+	p := poster{client: d.Client, retries: d.Retries, retryDelay: d.RetryDelay}
+	return p.post(ctx, d.URL, discordPayload{Username: d.Username, AvatarURL: d.AvatarURL, Embeds: []discordEmbed{embed}})
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
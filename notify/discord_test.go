@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscord_Notify tests the Discord Notify() method.
+func TestDiscord_Notify(t *testing.T) {
+	var calls int32
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Discord rate limits with a retry_after in seconds.
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"message":"You are being rate limited.","retry_after":0.01,"global":false}`)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	discord := &Discord{URL: server.URL, Username: "Nitrado", RetryDelay: time.Hour}
+	err := discord.Notify(context.Background(), Message{
+		Title:  "Survivor joined My Server",
+		Text:   strings.Repeat("a", 5000),
+		Color:  ColorBlue,
+		Fields: []Field{{Name: "ID", Value: "76561197960287930", Inline: true}},
+		Time:   time.Unix(1622359920, 0),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.JSONEq(t, `{
+		"username": "Nitrado",
+		"embeds": [{
+			"title": "Survivor joined My Server",
+			"description": "`+strings.Repeat("a", 4095)+`…",
+			"color": 3447003,
+			"fields": [{"name": "ID", "value": "76561197960287930", "inline": true}],
+			"timestamp": "2021-05-30T07:32:00Z"
+		}]
+	}`, body)
+}
+
+// TestTruncate tests the truncate() function.
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 5))
+	assert.Equal(t, "shor…", truncate("shorter", 5))
+	assert.Equal(t, "ünï…", truncate("ünïcode", 4))
+}
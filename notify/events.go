@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+)
+
+const (
+	defaultInterval      = time.Minute
+	defaultExpiryWarning = 3 * 24 * time.Hour
+)
+
+// StatusChanged returns the message for a game server changing status.
+func StatusChanged(svc nitrado.Service, from, to string) Message {
+	color := ColorOrange
+	switch to {
+	case "started":
+		color = ColorGreen
+	case "stopped":
+		color = ColorRed
+	}
+	return Message{
+		Title: fmt.Sprintf("%s is %s", serviceName(svc), to),
+		Text:  fmt.Sprintf("The game server changed from %s to %s.", from, to),
+		Color: color,
+		Time:  time.Now(),
+	}
+}
+
+// PlayerChanged returns the message for an event of a PlayerWatcher.
+func PlayerChanged(svc nitrado.Service, e nitrado.PlayerEvent) Message {
+	m := Message{Color: ColorBlue, Time: e.Time}
+	switch e.Type {
+	case nitrado.PlayerJoined:
+		m.Title = fmt.Sprintf("%s joined %s", e.Player.Name, serviceName(svc))
+	case nitrado.PlayerLeft:
+		m.Title = fmt.Sprintf("%s left %s", e.Player.Name, serviceName(svc))
+		m.Fields = append(m.Fields, Field{Name: "Session", Value: e.Session.Round(time.Second).String(), Inline: true})
+	case nitrado.PlayerRenamed:
+		m.Title = fmt.Sprintf("%s is now known as %s", e.PreviousName, e.Player.Name)
+	default:
+		m.Title = fmt.Sprintf("%s %s", e.Player.Name, e.Type)
+	}
+	if e.Player.ID != "" {
+		m.Fields = append(m.Fields, Field{Name: "ID", Value: e.Player.ID, Inline: true})
+	}
+	return m
+}
+
+// Expiring returns the message for a service which is about to be
+// suspended, using its SuspendingIn.
+func Expiring(svc nitrado.Service) Message {
+	in := time.Duration(svc.SuspendingIn) * time.Second
+	m := Message{
+		Title: fmt.Sprintf("%s expires in %s", serviceName(svc), humanDuration(in)),
+		Text:  "Extend the service to keep it running.",
+		Color: ColorRed,
+		Time:  time.Now(),
+	}
+	if svc.SuspendDate != "" {
+		m.Fields = append(m.Fields, Field{Name: "Suspended on", Value: svc.SuspendDate, Inline: true})
+	}
+	return m
+}
+
+// Watcher polls a game server and notifies status changes, player events
+// and the service being about to expire.
+type Watcher struct {
+	client   *nitrado.Client
+	svc      nitrado.Service
+	notifier Notifier
+	players  *nitrado.PlayerWatcher
+
+	Interval time.Duration // How often to poll, defaults to 1 minute.
+
+	// ExpiryWarning is how long before the service is suspended to notify,
+	// defaults to 3 days. The warning is sent once, and again after the
+	// service has been extended.
+	ExpiryWarning time.Duration
+
+	// PlayerEvents are the kinds of player events to notify, defaults to
+	// joins only.
+	PlayerEvents []nitrado.PlayerEventType
+
+	// OnError is called when a poll or a notification fails.
+	OnError func(error)
+
+	mu      sync.Mutex
+	status  string
+	warned  bool
+	started bool
+}
+
+// NewWatcher returns a Watcher for the game server of svc, which sends
+// notifications to n. Players who are online when it starts are not
+// notified.
+func NewWatcher(c *nitrado.Client, svc nitrado.Service, n Notifier) *Watcher {
+	players := nitrado.NewPlayerWatcher(c, svc)
+	players.IgnoreInitial = true
+	return &Watcher{client: c, svc: svc, notifier: n, players: players}
+}
+
+// Run polls immediately and then every Interval until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks the game server, its players and the service once, sending
+// a notification for every change since the previous poll. The status of
+// the first poll is not notified. A check whose request fails, or returns
+// an API error, is reported to OnError and skipped.
+func (w *Watcher) Poll(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if gs, _, err := w.client.GameServers.Get(w.svc.ID); err != nil {
+		w.report(fmt.Errorf("getting game server: %w", err))
+	} else {
+		if w.started && gs.Status != w.status {
+			w.notify(ctx, StatusChanged(w.svc, w.status, gs.Status))
+		}
+		w.status = gs.Status
+		w.started = true
+	}
+
+	events := make(chan nitrado.PlayerEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.players.Poll(ctx, events)
+		close(events)
+	}()
+	for e := range events {
+		if w.wantsPlayerEvent(e.Type) {
+			w.notify(ctx, PlayerChanged(w.svc, e))
+		}
+	}
+	if err := <-done; err != nil {
+		w.report(fmt.Errorf("listing players: %w", err))
+	}
+
+	svc, _, err := w.client.Services.Get(w.svc.ID)
+	if err != nil {
+		w.report(fmt.Errorf("getting service: %w", err))
+		return
+	}
+	w.svc.SuspendingIn, w.svc.SuspendDate = svc.SuspendingIn, svc.SuspendDate
+	warning := w.ExpiryWarning
+	if warning <= 0 {
+		warning = defaultExpiryWarning
+	}
+	// A service without a SuspendingIn is not known to be expiring.
+	expiring := w.svc.SuspendingIn > 0 && time.Duration(w.svc.SuspendingIn)*time.Second < warning
+	if expiring && !w.warned {
+		w.notify(ctx, Expiring(w.svc))
+	}
+	w.warned = expiring
+}
+
+func (w *Watcher) wantsPlayerEvent(t nitrado.PlayerEventType) bool {
+	if len(w.PlayerEvents) == 0 {
+		return t == nitrado.PlayerJoined
+	}
+	for _, want := range w.PlayerEvents {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) notify(ctx context.Context, m Message) {
+	if err := w.notifier.Notify(ctx, m); err != nil {
+		w.report(fmt.Errorf("notifying %q: %w", m.Title, err))
+	}
+}
+
+func (w *Watcher) report(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// serviceName returns the name of a service, or its ID when it has none.
+func serviceName(svc nitrado.Service) string {
+	if svc.Details.Name != "" {
+		return svc.Details.Name
+	}
+	return "Service " + strconv.Itoa(svc.ID)
+}
+
+// humanDuration formats d in days, hours or minutes.
+func humanDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	default:
+		return fmt.Sprintf("%d minutes", d/time.Minute)
+	}
+}
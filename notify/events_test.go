@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danstis/go-nitrado/nitrado"
+	"github.com/danstis/go-nitrado/nitradotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatcher_Poll tests the Watcher Poll() method.
+func TestWatcher_Poll(t *testing.T) {
+	server := nitradotest.NewServer()
+	defer server.Close()
+	var titles []string
+	svc := nitrado.Service{ID: 7654321}
+	svc.Details.Name = "My Server"
+	// set replaces the game server, its players and when it is suspended.
+	set := func(status string, suspendingIn int, players ...nitrado.Player) {
+		svc := svc
+		svc.SuspendingIn = suspendingIn
+		server.AddGameServer(svc, nitrado.GameServer{Status: status})
+		server.SetPlayers(svc.ID, players...)
+	}
+	survivor := nitrado.Player{Name: "Survivor", ID: "1", Online: true}
+	bandit := nitrado.Player{Name: "Bandit", ID: "2", Online: true}
+	watcher := NewWatcher(server.Client(), svc, NotifierFunc(func(ctx context.Context, m Message) error {
+		titles = append(titles, m.Title)
+		return nil
+	}))
+	var errs []error
+	watcher.OnError = func(err error) { errs = append(errs, err) }
+	day := 24 * 60 * 60
+
+	// The initial status and players are not notified.
+	set("started", 30*day, survivor)
+	watcher.Poll(context.Background())
+	assert.Empty(t, titles)
+
+	// Players are not compared while the server is stopped.
+	set("stopped", 2*day)
+	watcher.Poll(context.Background())
+	assert.Equal(t, []string{"My Server is stopped", "My Server expires in 2 days"}, titles)
+
+	// The expiry is only notified once.
+	titles = nil
+	set("started", 2*day, survivor, bandit)
+	watcher.Poll(context.Background())
+	assert.Equal(t, []string{"My Server is started", "Bandit joined My Server"}, titles)
+
+	// Leaves are not notified by default.
+	titles = nil
+	set("started", day, survivor)
+	watcher.Poll(context.Background())
+	assert.Empty(t, titles)
+
+	// The service was extended, and leaves are notified.
+	watcher.PlayerEvents = []nitrado.PlayerEventType{nitrado.PlayerJoined, nitrado.PlayerLeft}
+	set("started", 30*day)
+	watcher.Poll(context.Background())
+	assert.Equal(t, []string{"Survivor left My Server"}, titles)
+
+	titles = nil
+	set("started", day)
+	watcher.Poll(context.Background())
+	assert.Equal(t, []string{"My Server expires in 24 hours"}, titles)
+
+	// A service without a SuspendingIn is not expiring.
+	titles = nil
+	set("started", 0)
+	watcher.Poll(context.Background())
+	assert.Empty(t, titles)
+	assert.Empty(t, errs)
+}
+
+// TestWatcher_Poll_apiError tests that API errors do not send notifications.
+func TestWatcher_Poll_apiError(t *testing.T) {
+	server := nitradotest.NewServer()
+	defer server.Close()
+	svc := nitrado.Service{ID: 7654321, SuspendingIn: 30 * 24 * 60 * 60}
+	svc.Details.Name = "My Server"
+	server.AddGameServer(svc, nitrado.GameServer{Status: "started"})
+	server.SetPlayers(svc.ID, nitrado.Player{Name: "Survivor", ID: "1", Online: true})
+
+	var titles []string
+	watcher := NewWatcher(server.Client(), svc, NotifierFunc(func(ctx context.Context, m Message) error {
+		titles = append(titles, m.Title)
+		return nil
+	}))
+	watcher.PlayerEvents = []nitrado.PlayerEventType{nitrado.PlayerJoined, nitrado.PlayerLeft}
+	var errs []error
+	watcher.OnError = func(err error) { errs = append(errs, err) }
+
+	watcher.Poll(context.Background())
+	require.Empty(t, errs)
+
+	server.Inject(nitradotest.Fault{Status: http.StatusServiceUnavailable})
+	watcher.Poll(context.Background())
+	assert.Empty(t, titles)
+	assert.Len(t, errs, 3)
+
+	server.ClearFaults()
+	watcher.Poll(context.Background())
+	assert.Empty(t, titles)
+}
+
+// TestMessages tests the StatusChanged(), PlayerChanged() and Expiring() functions.
+func TestMessages(t *testing.T) {
+	svc := nitrado.Service{ID: 7654321, SuspendingIn: 5 * 24 * 60 * 60}
+
+	m := StatusChanged(svc, "started", "stopped")
+	assert.Equal(t, "Service 7654321 is stopped", m.Title)
+	assert.Equal(t, "The game server changed from started to stopped.", m.Text)
+	assert.Equal(t, ColorRed, m.Color)
+
+	now := time.Unix(1622359920, 0)
+	m = PlayerChanged(svc, nitrado.PlayerEvent{Type: nitrado.PlayerLeft, Time: now, Player: nitrado.Player{Name: "Survivor", ID: "1"}, Session: 90*time.Minute + 400*time.Millisecond})
+	assert.Equal(t, Message{
+		Title:  "Survivor left Service 7654321",
+		Color:  ColorBlue,
+		Fields: []Field{{Name: "Session", Value: "1h30m0s", Inline: true}, {Name: "ID", Value: "1", Inline: true}},
+		Time:   now,
+	}, m)
+	m = PlayerChanged(svc, nitrado.PlayerEvent{Type: nitrado.PlayerRenamed, Player: nitrado.Player{Name: "Bandit"}, PreviousName: "Survivor"})
+	assert.Equal(t, "Survivor is now known as Bandit", m.Title)
+
+	m = Expiring(svc)
+	assert.Equal(t, "Service 7654321 expires in 5 days", m.Title)
+	assert.Empty(t, m.Fields)
+}
@@ -0,0 +1,170 @@
+// Package notify sends notifications about Nitrado services to chat services
+// and webhooks, such as game server status changes, player joins and
+// services which are about to expire.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetries    = 3
+	defaultRetryDelay = time.Second
+)
+
+// Colors for Message.Color.
+const (
+	ColorGreen  = 0x2ecc71
+	ColorOrange = 0xe67e22
+	ColorRed    = 0xe74c3c
+	ColorBlue   = 0x3498db
+)
+
+// Field is a named value shown with a Message.
+type Field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// Message is a notification.
+type Message struct {
+	Title  string    `json:"title"`
+	Text   string    `json:"text,omitempty"`
+	URL    string    `json:"url,omitempty"`
+	Color  int       `json:"color,omitempty"` // An RGB color such as ColorRed, used by Discord for the embed.
+	Fields []Field   `json:"fields,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// Notifier sends messages.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(ctx context.Context, m Message) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, m Message) error {
+	return f(ctx, m)
+}
+
+// StatusError is returned when a webhook responds with an unexpected status.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook returned %s", e.Status)
+}
+
+// poster posts JSON, retrying on rate limits, server errors and network
+// errors.
+type poster struct {
+	client     *http.Client
+	header     http.Header
+	retries    int
+	retryDelay time.Duration
+}
+
+// post posts body to url. A rate limited request is retried after the delay
+// asked for by the server, other failures after an exponential backoff.
+func (p poster) post(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	retries := p.retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	delay := p.retryDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		wait, err := p.try(ctx, client, url, data)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt >= retries {
+			return err
+		}
+		if wait == 0 {
+			wait = delay << attempt
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// try makes a single request. It returns the delay before the next attempt,
+// zero for the default backoff, or a negative delay when the request should
+// not be retried.
+func (p poster) try(ctx context.Context, client *http.Client, url string, data []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return -1, err
+	}
+	for k, v := range p.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp.Header, respBody), &StatusError{resp.StatusCode, resp.Status}
+	case resp.StatusCode >= 500:
+		return 0, &StatusError{resp.StatusCode, resp.Status}
+	default:
+		return -1, &StatusError{resp.StatusCode, resp.Status}
+	}
+}
+
+// retryAfter returns the delay asked for by a rate limited response, from
+// the retry_after field used by Discord or the Retry-After header. It
+// returns zero when neither is set.
+func retryAfter(header http.Header, body []byte) time.Duration {
+	var discord struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if json.Unmarshal(body, &discord) == nil && discord.RetryAfter > 0 {
+		return time.Duration(discord.RetryAfter * float64(time.Second))
+	}
+	if s, err := strconv.ParseFloat(header.Get("Retry-After"), 64); err == nil && s > 0 {
+		return time.Duration(s * float64(time.Second))
+	}
+	return 0
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Webhook is a Notifier which posts each Message as JSON to a URL.
+type Webhook struct {
+	URL    string
+	Header http.Header  // Extra headers to send, such as an Authorization header.
+	Client *http.Client // The client to post with, defaults to http.DefaultClient.
+
+	Retries    int           // How often to retry a failed post, defaults to 3. Set a negative value to disable retries.
+	RetryDelay time.Duration // The delay before the first retry, defaults to 1 second, doubling with each retry.
+}
+
+// Notify posts the message. Rate limited posts, server errors and network
+// errors are retried, other responses than a 2xx status return a
+// *StatusError.
+func (w *Webhook) Notify(ctx context.Context, m Message) error {
+	p := poster{client: w.Client, header: w.Header, retries: w.Retries, retryDelay: w.RetryDelay}
+	return p.post(ctx, w.URL, m)
+}
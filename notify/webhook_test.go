@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWebhook_Notify tests the Webhook Notify() method.
+func TestWebhook_Notify(t *testing.T) {
+	var got Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	m := Message{Title: "My Server is started", Color: ColorGreen, Fields: []Field{{Name: "ID", Value: "1"}}, Time: time.Unix(1622359920, 0).UTC()}
+	webhook := &Webhook{URL: server.URL, Header: http.Header{"Authorization": {"Bearer secret"}}}
+	require.Nil(t, webhook.Notify(context.Background(), m))
+	assert.Equal(t, m, got)
+}
+
+// TestWebhook_NotifyRetries tests that the Webhook Notify() method retries failed posts.
+func TestWebhook_NotifyRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter, attempt int32)
+		retries int
+		calls   int32
+		status  int
+	}{
+		{
+			name: "Rate limited with Retry-After",
+			respond: func(w http.ResponseWriter, attempt int32) {
+				if attempt == 1 {
+					w.Header().Set("Retry-After", "0.01")
+					w.WriteHeader(http.StatusTooManyRequests)
+				}
+			},
+			calls: 2,
+		},
+		{
+			name: "Server error",
+			respond: func(w http.ResponseWriter, attempt int32) {
+				if attempt < 3 {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			},
+			calls: 3,
+		},
+		{
+			name: "Retries exhausted",
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			retries: 2,
+			calls:   3,
+			status:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "Retries disabled",
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			retries: -1,
+			calls:   1,
+			status:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "Client error",
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			calls:  1,
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.respond(w, atomic.AddInt32(&calls, 1))
+			}))
+			defer server.Close()
+
+			webhook := &Webhook{URL: server.URL, Retries: tt.retries, RetryDelay: time.Millisecond}
+			err := webhook.Notify(context.Background(), Message{Title: "test"})
+			assert.Equal(t, tt.calls, atomic.LoadInt32(&calls))
+			if tt.status == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			var statusErr *StatusError
+			require.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, tt.status, statusErr.StatusCode)
+		})
+	}
+}
+
+// TestWebhook_NotifyCancelled tests that the Webhook Notify() method stops retrying when the context is cancelled.
+func TestWebhook_NotifyCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := (&Webhook{URL: server.URL}).Notify(ctx, Message{Title: "test"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}